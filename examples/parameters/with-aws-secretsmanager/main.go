@@ -44,6 +44,9 @@ func main() {
 	// This parameter should be stored as key/value secret in AWS Secrets Manager. The result of parsing will have a JSON string representing this parameter.
 	parameters.Add("EXAMPLE_SECRET_JSON", "", "An example key/value parameter from AWS", true, parameters.Options{SecretsManagerEnable: true})
 
+	// This parameter gets the "title" field of the EXAMPLE_SECRET_JSON key/value secret, so several parameters can share one secret.
+	parameters.Add("EXAMPLE_SECRET_TITLE", "", "An example field of the key/value parameter from AWS", false, parameters.Options{SecretsManagerEnable: true, SecretKey: "EXAMPLE_SECRET_JSON#title"})
+
 	// If this parameter wan't provided and doesn't exist in AWS Secrets Manager
 	// the default value should be in the result of parse() function.
 	parameters.Add("EXAMPLE_SECRET_DOES_NOT_EXIST", "default value", "", false, parameters.Options{SecretsManagerEnable: true})
@@ -63,7 +66,8 @@ func main() {
 
 	log.Println("Reads all parameter using for ... range")
 	log.Println("_______________")
-	for k, value := range myParams {
+	// Values of sensitive parameters are redacted by parameters.Redact()
+	for k, value := range parameters.Redact(myParams) {
 		log.Printf("%v: %+v", k, value)
 	}
 	log.Println("_______________")
@@ -35,7 +35,8 @@ func main() {
 
 	log.Println("Reads all parameter using for ... range:")
 	log.Println("_______________")
-	for k, value := range myParams {
+	// Values of sensitive parameters are redacted by parameters.Redact()
+	for k, value := range parameters.Redact(myParams) {
 		log.Printf("%v: %+v", k, value)
 	}
 	log.Println("_______________")
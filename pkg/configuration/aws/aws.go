@@ -2,6 +2,7 @@ package aws
 
 import (
 	"github.com/barchart/common-go/pkg/configuration/aws/dynamo"
+	"github.com/barchart/common-go/pkg/configuration/aws/parameterstore"
 	"github.com/barchart/common-go/pkg/configuration/aws/s3"
 	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
 	"github.com/barchart/common-go/pkg/configuration/aws/ses"
@@ -17,4 +18,5 @@ type AWS struct {
 	SES            *map[string]ses.SES
 	S3             *map[string]s3.S3
 	SecretsManager *secretsmanager.SecretsManager
+	ParameterStore *parameterstore.ParameterStore
 }
@@ -0,0 +1,116 @@
+package parameterstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/barchart/common-go/pkg/logger"
+)
+
+var log = logger.Log
+
+// ErrNotFound is returned if the parameter doesn't exist in AWS SSM Parameter Store
+var ErrNotFound = errors.New("parameter not found")
+
+// ParameterStore is a type of AWS SSM Parameter Store configuration and provider
+type ParameterStore struct {
+	Region string `validate:"required"`
+	ssm    *ssm.SSM
+}
+
+// New creates new AWS SSM Parameter Store instance. Additional configs are merged to the config with the region,
+// e.g. to set an endpoint. An error of the session is logged.
+func New(region string, configs ...*aws.Config) *ParameterStore {
+	parameterStore, err := NewE(region, configs...)
+
+	if err != nil {
+		log.Println("err " + err.Error())
+	}
+
+	return parameterStore
+}
+
+// NewE creates new AWS SSM Parameter Store instance like New, but returns an error of the session.
+func NewE(region string, configs ...*aws.Config) (*ParameterStore, error) {
+	parameterStore := ParameterStore{}
+	parameterStore.Region = region
+
+	sess, err := session.NewSession()
+	if err != nil {
+		return &parameterStore, err
+	}
+
+	parameterStore.ssm = ssm.New(sess, append([]*aws.Config{aws.NewConfig().WithRegion(region)}, configs...)...)
+
+	return &parameterStore, nil
+}
+
+// GetValue returns value from AWS SSM Parameter Store. A SecureString value is decrypted.
+// Returns ErrNotFound if the parameter doesn't exist.
+func (parameterStore ParameterStore) GetValue(name string) (string, error) {
+	return parameterStore.GetValueWithContext(context.Background(), name)
+}
+
+// GetValueWithContext returns value from AWS SSM Parameter Store like GetValue. The request is canceled when the context is done.
+func (parameterStore ParameterStore) GetValueWithContext(ctx context.Context, name string) (string, error) {
+	input := &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	}
+
+	result, err := parameterStore.ssm.GetParameterWithContext(ctx, input)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ssm.ErrCodeParameterNotFound {
+			return "", fmt.Errorf("%w: %v", ErrNotFound, name)
+		}
+
+		return "", err
+	}
+
+	return aws.StringValue(result.Parameter.Value), nil
+}
+
+// GetValuesByPath returns all values from AWS SSM Parameter Store under the path including nested paths.
+// The result is keyed by full names of parameters. SecureString values are decrypted.
+func (parameterStore ParameterStore) GetValuesByPath(path string) (map[string]string, error) {
+	return parameterStore.GetValuesByPathWithContext(context.Background(), path)
+}
+
+// GetValuesByPathWithContext returns all values under the path like GetValuesByPath. Requests are canceled when the context is done.
+func (parameterStore ParameterStore) GetValuesByPathWithContext(ctx context.Context, path string) (map[string]string, error) {
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	}
+
+	values := map[string]string{}
+
+	err := parameterStore.ssm.GetParametersByPathPagesWithContext(ctx, input, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, parameter := range page.Parameters {
+			values[aws.StringValue(parameter.Name)] = aws.StringValue(parameter.Value)
+		}
+
+		return true
+	})
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, err
+	}
+
+	return values, nil
+}
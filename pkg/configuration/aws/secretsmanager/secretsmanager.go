@@ -1,13 +1,16 @@
 package secretsmanager
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/barchart/common-go/pkg/logger"
@@ -19,12 +22,57 @@ func init() {
 	log.SetReportCaller(true)
 }
 
+// ErrNotFound is returned if the secret doesn't exist in AWS Secrets Manager
+var ErrNotFound = errors.New("secret not found")
+
 // SecretsManager is a type of AWS Secrets Manager configuration and provider
 type SecretsManager struct {
 	Region string `validate:"required"`
+	Retry  RetryPolicy
 	sm     *secretsmanager.SecretsManager
 }
 
+// RetryPolicy is a policy of retries of throttled requests and internal service errors.
+// The delay is doubled after each retry starting from MinDelay up to MaxDelay, retries aren't delayed if MinDelay is zero.
+type RetryPolicy struct {
+	MaxRetries int
+	MinDelay   time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of new AWS Secrets Manager instances
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinDelay:   100 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+}
+
+// delay returns a delay before the retry with the specified number.
+// Retries aren't delayed if MinDelay is zero.
+func (policy RetryPolicy) delay(retry int) time.Duration {
+	if policy.MinDelay <= 0 {
+		return 0
+	}
+
+	delay := policy.MinDelay << uint(retry)
+
+	// the shifted delay loses bits if it overflows
+	if delay>>uint(retry) != policy.MinDelay || delay > policy.MaxDelay {
+		return policy.MaxDelay
+	}
+
+	return delay
+}
+
+// isRetryable returns true if the request failed due to throttling or an error which can be retried
+func isRetryable(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == secretsmanager.ErrCodeInternalServiceError {
+		return true
+	}
+
+	return request.IsErrorThrottle(err) || request.IsErrorRetryable(err)
+}
+
 // isStringJSON returns true/false if the provided string is JSON
 func isStringJSON(str string) bool {
 	var jsonStr map[string]interface{}
@@ -32,32 +80,67 @@ func isStringJSON(str string) bool {
 	return err == nil
 }
 
-// New creates new AWS Secrets Manager instance
-func New(region string) *SecretsManager {
+// New creates new AWS Secrets Manager instance. Additional configs are applied after the region, e.g. to set an endpoint.
+// Retries of the AWS SDK are disabled by default, requests are retried by the Retry policy. An error of the session is logged.
+func New(region string, configs ...*aws.Config) *SecretsManager {
+	secretsManager, err := NewE(region, configs...)
+
+	if err != nil {
+		log.Println("err " + err.Error())
+	}
+
+	return secretsManager
+}
+
+// NewE creates new AWS Secrets Manager instance like New, but returns an error of the session.
+func NewE(region string, configs ...*aws.Config) (*SecretsManager, error) {
 	secretsManager := SecretsManager{}
 	secretsManager.Region = region
+	secretsManager.Retry = DefaultRetryPolicy
 
 	sess, err := session.NewSession()
-
 	if err != nil {
-		log.Println("err " + err.Error())
+		return &secretsManager, err
 	}
 
-	secretsManager.sm = secretsmanager.New(sess, aws.NewConfig().WithRegion(region))
+	secretsManager.sm = secretsmanager.New(sess, append([]*aws.Config{aws.NewConfig().WithRegion(region).WithMaxRetries(0)}, configs...)...)
 
-	return &secretsManager
+	return &secretsManager, nil
 }
 
 // GetValue returns value from AWS Secrets Manager.
 // Returns 3 variables: value, isJSON, err
 func (secretsManager SecretsManager) GetValue(secretName string) (string, bool, error) {
+	return secretsManager.GetValueWithContext(context.Background(), secretName)
+}
+
+// GetValueWithContext returns value from AWS Secrets Manager like GetValue. The request is canceled when the context is done.
+// Throttled requests and internal service errors are retried by the Retry policy. Returns ErrNotFound if the secret doesn't exist.
+func (secretsManager SecretsManager) GetValueWithContext(ctx context.Context, secretName string) (string, bool, error) {
 	input := &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(secretName),
 	}
 
-	secretResult, err := secretsManager.sm.GetSecretValue(input)
+	secretResult, err := secretsManager.sm.GetSecretValueWithContext(ctx, input)
+
+	for retry := 0; err != nil && retry < secretsManager.Retry.MaxRetries && isRetryable(err); retry++ {
+		timer := time.NewTimer(secretsManager.Retry.delay(retry))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", false, ctx.Err()
+		case <-timer.C:
+		}
+
+		secretResult, err = secretsManager.sm.GetSecretValueWithContext(ctx, input)
+	}
 
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
+
 		if aerr, ok := err.(awserr.Error); ok {
 			switch aerr.Code() {
 			case secretsmanager.ErrCodeDecryptionFailure:
@@ -73,7 +156,7 @@ func (secretsManager SecretsManager) GetValue(secretName string) (string, bool,
 				return "", false, errors.New(fmt.Sprintln(secretsmanager.ErrCodeInvalidRequestException, aerr.Error()))
 
 			case secretsmanager.ErrCodeResourceNotFoundException:
-				return "", false, errors.New(fmt.Sprintln(secretsmanager.ErrCodeResourceNotFoundException, aerr.Error()))
+				return "", false, fmt.Errorf("%w: %v", ErrNotFound, fmt.Sprintln(secretsmanager.ErrCodeResourceNotFoundException, aerr.Error()))
 			}
 		} else {
 			log.Println(err.Error())
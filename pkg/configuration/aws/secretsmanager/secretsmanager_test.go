@@ -0,0 +1,23 @@
+package secretsmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, MinDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.delay(0), "the first delay should be MinDelay")
+	assert.Equal(t, 400*time.Millisecond, policy.delay(2), "the delay should be doubled after each retry")
+	assert.Equal(t, time.Second, policy.delay(4), "the delay should be limited by MaxDelay")
+	assert.Equal(t, time.Second, policy.delay(62), "the delay should be limited by MaxDelay if it overflows")
+	assert.Equal(t, time.Second, policy.delay(100), "the delay should be limited by MaxDelay if it overflows")
+
+	policy = RetryPolicy{MaxRetries: 3, MaxDelay: 5 * time.Second}
+
+	assert.Equal(t, time.Duration(0), policy.delay(0), "retries shouldn't be delayed without MinDelay")
+	assert.Equal(t, time.Duration(0), policy.delay(2), "retries shouldn't be delayed without MinDelay")
+}
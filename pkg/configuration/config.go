@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/aws"
+
 	. "github.com/barchart/common-go/pkg/configuration/aws"
 	. "github.com/barchart/common-go/pkg/configuration/aws/dynamo"
+	"github.com/barchart/common-go/pkg/configuration/aws/parameterstore"
 	. "github.com/barchart/common-go/pkg/configuration/aws/s3"
 	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
 	. "github.com/barchart/common-go/pkg/configuration/aws/ses"
@@ -76,6 +79,11 @@ func GetSecretsManager() (secretsmanager.SecretsManager, error) {
 	return config.getSecretsManager()
 }
 
+// GetParameterStore returns ParameterStore configuration
+func GetParameterStore() (parameterstore.ParameterStore, error) {
+	return config.getParameterStore()
+}
+
 // GetStage returns current stage
 func GetStage() string {
 	return config.getStage()
@@ -131,8 +139,24 @@ func SetSQS(key string, region string, prefix string, queue string) error {
 }
 
 // SetSecretsManager creates a Secrets Manager instance and sets it into the instance of the configuration
-func SetSecretsManager(region string) {
-	config.setSecretsManager(region)
+func SetSecretsManager(region string, configs ...*aws.Config) {
+	config.setSecretsManager(region, configs...)
+}
+
+// SetSecretsManagerE creates a Secrets Manager instance and sets it into the instance of the configuration.
+// Returns an error of the AWS session, the instance isn't set in this case.
+func SetSecretsManagerE(region string, configs ...*aws.Config) error {
+	return config.setSecretsManagerE(region, configs...)
+}
+
+// SetSecretsManagerInstance sets the existing Secrets Manager instance into the instance of the configuration
+func SetSecretsManagerInstance(secretsManager *secretsmanager.SecretsManager) {
+	config.setSecretsManagerInstance(secretsManager)
+}
+
+// SetParameterStore creates a SSM Parameter Store instance and sets it into the instance of the configuration
+func SetParameterStore(region string) {
+	config.setParameterStore(region)
 }
 
 // SetStage sets the current stage
@@ -276,6 +300,14 @@ func (cfg *Config) getSecretsManager() (secretsmanager.SecretsManager, error) {
 	return *cfg.AWS.SecretsManager, nil
 }
 
+func (cfg *Config) getParameterStore() (parameterstore.ParameterStore, error) {
+	if cfg.AWS == nil || cfg.AWS.ParameterStore == nil {
+		return parameterstore.ParameterStore{}, errors.New("parameter store configuration hasn't been set")
+	}
+
+	return *cfg.AWS.ParameterStore, nil
+}
+
 func (cfg Config) getStage() string {
 	return cfg.Stage
 }
@@ -473,12 +505,43 @@ func (cfg *Config) setSQS(key string, region string, prefix string, queue string
 	return nil
 }
 
-func (cfg *Config) setSecretsManager(region string) {
+func (cfg *Config) setSecretsManager(region string, configs ...*aws.Config) {
+	if cfg.AWS == nil {
+		cfg.AWS = &AWS{}
+	}
+
+	cfg.AWS.SecretsManager = secretsmanager.New(region, configs...)
+}
+
+func (cfg *Config) setSecretsManagerE(region string, configs ...*aws.Config) error {
+	secretsManager, err := secretsmanager.NewE(region, configs...)
+	if err != nil {
+		return err
+	}
+
+	if cfg.AWS == nil {
+		cfg.AWS = &AWS{}
+	}
+
+	cfg.AWS.SecretsManager = secretsManager
+
+	return nil
+}
+
+func (cfg *Config) setSecretsManagerInstance(secretsManager *secretsmanager.SecretsManager) {
+	if cfg.AWS == nil {
+		cfg.AWS = &AWS{}
+	}
+
+	cfg.AWS.SecretsManager = secretsManager
+}
+
+func (cfg *Config) setParameterStore(region string) {
 	if cfg.AWS == nil {
 		cfg.AWS = &AWS{}
 	}
 
-	cfg.AWS.SecretsManager = secretsmanager.New(region)
+	cfg.AWS.ParameterStore = parameterstore.New(region)
 }
 
 func (cfg *Config) setStage(stage string) {
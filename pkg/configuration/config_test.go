@@ -3,6 +3,7 @@ package configuration
 import (
 	"testing"
 
+	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
 	"github.com/barchart/common-go/pkg/configuration/database"
 	"github.com/stretchr/testify/assert"
 )
@@ -92,3 +93,16 @@ func TestSetSNS(t *testing.T) {
 	assert.Equal(t, topic, sns.Topic, "topic should be set correctly")
 	assert.Equal(t, prefix, sns.Prefix, "prefix should be set correctly")
 }
+
+func TestSetSecretsManagerInstance(t *testing.T) {
+	expectedRetry := secretsmanager.RetryPolicy{MaxRetries: 1}
+
+	secretsManager := secretsmanager.New("us-east-1")
+	secretsManager.Retry = expectedRetry
+
+	SetSecretsManagerInstance(secretsManager)
+
+	sm, getErr := GetSecretsManager()
+	assert.Nil(t, getErr, "get error should be nil")
+	assert.Equal(t, expectedRetry, sm.Retry, "secrets manager should be set correctly")
+}
@@ -0,0 +1,92 @@
+package parameters
+
+import "flag"
+
+// names returns the parameter name followed by its aliases
+func (p Parameter) names() []string {
+	return append([]string{p.Name}, p.Options.Aliases...)
+}
+
+// setupFlag marks the flag of a sensitive parameter as sensitive and defines flags of aliases which share the value of the flag
+func (s *ParameterSet) setupFlag(param Parameter) {
+	if param.IsSensitive() {
+		s.flags.Sensitive(param.flagName)
+	}
+
+	flg := s.flags.Lookup(param.flagName)
+
+	for _, alias := range param.Options.Aliases {
+		s.flags.Var(flg.Value, s.naming.flagName(alias), flg.Usage+" (deprecated alias of "+param.flagName+")")
+	}
+}
+
+// visitFlags records names of flags which were set during the parsing
+func (s *ParameterSet) visitFlags() {
+	s.setFlags = map[string]bool{}
+	s.flags.Visit(func(flg *flag.Flag) {
+		s.setFlags[flg.Name] = true
+	})
+}
+
+// usedFlagName returns a name of the flag which was set for the parameter, the flag of an alias is reported as deprecated
+func (s *ParameterSet) usedFlagName(param Parameter) string {
+	if s.setFlags[param.flagName] {
+		return param.flagName
+	}
+
+	for _, alias := range param.Options.Aliases {
+		if name := s.naming.flagName(alias); s.setFlags[name] {
+			s.warnAlias(param, SourceFlag, name)
+			return name
+		}
+	}
+
+	return param.flagName
+}
+
+// lookupParamEnv returns a value of the environment variable of the parameter or its aliases.
+// The stage sensitive parameter is searched by NAME_STAGE before NAME for the name and each alias.
+// All lookups are recorded, the environment variable of an alias is reported as deprecated.
+func (s *ParameterSet) lookupParamEnv(param Parameter) (raw string, source string, name string, found bool) {
+	for i, alias := range param.names() {
+		names := make([]string, 0, 2)
+		if staged, ok := s.stageName(param, alias); ok {
+			names = append(names, s.naming.envName(staged))
+		}
+
+		if i == 0 {
+			names = append(names, param.envName)
+		} else {
+			names = append(names, s.naming.envName(alias))
+		}
+
+		for _, name = range names {
+			raw, source, found = s.lookupEnv(name)
+			if found {
+				s.trace(param, source, name, true)
+				if i > 0 {
+					s.warnAlias(param, source, name)
+				}
+				return raw, source, name, true
+			}
+
+			s.trace(param, SourceEnv, name, false)
+		}
+	}
+
+	return "", "", param.envName, false
+}
+
+// warnAlias logs a warning that the value of the parameter was found by the deprecated alias
+func (s *ParameterSet) warnAlias(param Parameter, source string, name string) {
+	if s.parsed {
+		return
+	}
+
+	log.Warnf("%v: %v %v is a deprecated alias of the %v parameter", s.name, source, name, param.Name)
+}
+
+// warnDeprecated logs a warning that the deprecated parameter was provided
+func (s *ParameterSet) warnDeprecated(param Parameter) {
+	log.Warnf("%v: the %v parameter is deprecated: %v", s.name, param.Name, param.Options.Deprecated)
+}
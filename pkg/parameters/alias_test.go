@@ -0,0 +1,62 @@
+package parameters
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSet_Aliases(t *testing.T) {
+	output := log.Out
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	defer log.SetOutput(output)
+
+	_ = os.Setenv("OLD_PORT", "2")
+	defer os.Unsetenv("OLD_PORT")
+
+	stub := newSecretsManagerStub(t, map[string]string{"OLD_PASSWORD": "secret"}, 0)
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("ALIAS_HOST", "", "A host", false, Options{Aliases: []string{"OLD_HOST"}})
+	set.AddInt("ALIAS_PORT", 0, "A port", false, Options{Aliases: []string{"OLD_PORT"}})
+	set.AddString("ALIAS_PASSWORD", "", "A password", false, Options{SecretsManagerEnable: true, Aliases: []string{"OLD_PASSWORD"}})
+	set.AddString("ALIAS_NAME", "", "A name", false, Options{Aliases: []string{"OLD_NAME"}})
+
+	setResult, err := set.ParseE([]string{"--OLD_HOST=flag", "--ALIAS_NAME=name"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "flag", setResult.GetString("ALIAS_HOST"), "should return a value of the flag of the alias")
+	assert.Equal(t, 2, setResult.GetInt("ALIAS_PORT"), "should return a value of the env variable of the alias")
+	assert.Equal(t, "secret", setResult.GetString("ALIAS_PASSWORD"), "should return a value of the secret of the alias")
+	assert.Equal(t, "name", setResult.GetString("ALIAS_NAME"), "should return a value of the flag of the parameter")
+
+	provenance, _ := set.Source("ALIAS_PORT")
+	assert.Equal(t, Lookup{Source: SourceEnv, Name: "OLD_PORT", Found: true}, provenance.Lookups[len(provenance.Lookups)-1], "should record the name of the alias")
+
+	assert.Contains(t, buffer.String(), "flag OLD_HOST is a deprecated alias of the ALIAS_HOST parameter", "should warn about the alias")
+	assert.Contains(t, buffer.String(), "env OLD_PORT is a deprecated alias of the ALIAS_PORT parameter", "should warn about the alias")
+	assert.Contains(t, buffer.String(), "secretsmanager OLD_PASSWORD is a deprecated alias of the ALIAS_PASSWORD parameter", "should warn about the alias")
+	assert.NotContains(t, buffer.String(), "ALIAS_NAME parameter", "shouldn't warn if the parameter name is used")
+}
+
+func TestParameterSet_Deprecated(t *testing.T) {
+	output := log.Out
+	buffer := &bytes.Buffer{}
+	log.SetOutput(buffer)
+	defer log.SetOutput(output)
+
+	set := NewSet("test")
+	set.AddString("DEPRECATED_HOST", "", "A host", false, Options{Deprecated: "use DB_HOST"})
+	set.AddString("DEPRECATED_PORT", "", "A port", false, Options{Deprecated: "use DB_PORT"})
+
+	_, err := set.ParseE([]string{"--DEPRECATED_HOST=host"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Contains(t, buffer.String(), "the DEPRECATED_HOST parameter is deprecated: use DB_HOST", "should warn about the provided parameter")
+	assert.NotContains(t, buffer.String(), "DEPRECATED_PORT", "shouldn't warn about the parameter which wasn't provided")
+}
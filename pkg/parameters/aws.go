@@ -0,0 +1,92 @@
+package parameters
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/barchart/common-go/pkg/configuration"
+	"github.com/barchart/common-go/pkg/configuration/aws/parameterstore"
+	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
+	"github.com/barchart/common-go/pkg/parameters/flags"
+)
+
+// remoteEnabled returns true if any parameter is searched in AWS Secrets Manager or AWS SSM Parameter Store
+func (s *ParameterSet) remoteEnabled() bool {
+	for _, param := range s.collection {
+		if param.Options.SecretsManagerEnable || param.Options.ParameterStoreEnable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadOffline enables the offline mode by the OFFLINE flag or env variable and warns that remote sources are skipped.
+// If the OFFLINE parameter is defined in the collection or the OFFLINE flag was defined by the application,
+// only SetOffline enables the offline mode.
+func (s *ParameterSet) loadOffline() error {
+	if _, ok := s.collection[OfflineParameter]; !ok {
+		if value, ok := s.flags.Lookup(OfflineParameter).Value.(*flags.BoolValue); ok {
+			if value.IsSet() {
+				s.offline = value.Get().(bool)
+			} else if env, source, ok := s.lookupEnv(OfflineParameter); ok {
+				offline, err := strconv.ParseBool(env)
+				if err != nil {
+					return &InvalidValueError{Name: OfflineParameter, Source: source, Raw: env, Err: flags.ErrParse}
+				}
+				s.offline = offline
+			}
+		}
+	}
+
+	if s.offline && s.remoteEnabled() {
+		log.Warnf("%v: the offline mode is enabled, AWS Secrets Manager and AWS SSM Parameter Store are skipped", s.name)
+	}
+
+	return nil
+}
+
+// secretsManager returns the AWS Secrets Manager client of the set, the client is created on the first call.
+// The client of the default set is set into the configuration package too.
+func (s *ParameterSet) secretsManager() (*secretsmanager.SecretsManager, error) {
+	s.awsMu.Lock()
+	defer s.awsMu.Unlock()
+
+	if s.sm == nil && s.smError == nil {
+		smm, err := secretsmanager.NewE(s.getAWSSecretsRegion(), s.awsConfigs...)
+		if err != nil {
+			s.smError = fmt.Errorf("unable to create the AWS Secrets Manager client: %w", err)
+			return nil, s.smError
+		}
+
+		if s.secretsRetry != nil {
+			smm.Retry = *s.secretsRetry
+		}
+
+		if s == defaultParams {
+			configuration.SetSecretsManagerInstance(smm)
+		}
+
+		s.sm = smm
+	}
+
+	return s.sm, s.smError
+}
+
+// parameterStore returns the AWS SSM Parameter Store client, the client is created on the first call
+func (s *ParameterSet) parameterStore() (*parameterstore.ParameterStore, error) {
+	s.awsMu.Lock()
+	defer s.awsMu.Unlock()
+
+	if s.ps == nil && s.psError == nil {
+		ps, err := parameterstore.NewE(s.getAWSSecretsRegion(), s.awsConfigs...)
+		if err != nil {
+			s.psError = fmt.Errorf("unable to create the AWS SSM Parameter Store client: %w", err)
+			return nil, s.psError
+		}
+
+		s.ps = ps
+	}
+
+	return s.ps, s.psError
+}
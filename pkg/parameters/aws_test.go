@@ -0,0 +1,129 @@
+package parameters
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSet_LazyAWS(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("AWS_HOST", "localhost", "A host", false)
+
+	_, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Nil(t, set.flags.Lookup(AwsRegionSecrets), "the region flag shouldn't be defined without secrets")
+	assert.Nil(t, set.sm, "the AWS Secrets Manager client shouldn't be created without secrets")
+	assert.Nil(t, set.ps, "the AWS SSM Parameter Store client shouldn't be created without parameters")
+
+	stub := newSecretsManagerStub(t, map[string]string{"AWS_PASSWORD": "secret"}, 0)
+	defer stub.Close()
+
+	set = NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("AWS_PASSWORD", "", "A password", false, Options{SecretsManagerEnable: true})
+
+	_, err = set.ParseE([]string{"--AWS_PASSWORD=flag"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.NotNil(t, set.flags.Lookup(AwsRegionSecrets), "the region flag should be defined with secrets")
+	assert.Nil(t, set.sm, "the AWS Secrets Manager client shouldn't be created if secrets aren't requested")
+}
+
+func TestParameterSet_Offline(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{"AWS_PASSWORD": "secret"}, 0)
+	defer stub.Close()
+
+	newOfflineSet := func() *ParameterSet {
+		set := NewSet("test")
+		set.SetAWSConfig(newAWSConfig(stub.Server))
+		set.AddString("AWS_PASSWORD", "default", "A password", false, Options{SecretsManagerEnable: true})
+		set.AddString("AWS_HOST", "default", "A host", false, Options{ParameterStoreEnable: true})
+		return set
+	}
+
+	set := newOfflineSet()
+	set.SetOffline(true)
+	setResult, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "default", setResult.GetString("AWS_PASSWORD"), "secrets should be skipped in the offline mode")
+	assert.Equal(t, "default", setResult.GetString("AWS_HOST"), "Parameter Store should be skipped in the offline mode")
+
+	setResult, err = newOfflineSet().ParseE([]string{"--OFFLINE"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "default", setResult.GetString("AWS_PASSWORD"), "the offline mode should be enabled by the flag")
+
+	_ = os.Setenv(OfflineParameter, "true")
+	setResult, err = newOfflineSet().ParseE([]string{})
+	_ = os.Unsetenv(OfflineParameter)
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "default", setResult.GetString("AWS_PASSWORD"), "the offline mode should be enabled by the env variable")
+	assert.Empty(t, stub.requests, "secrets shouldn't be requested in the offline mode")
+
+	set = NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("AWS_PASSWORD", "default", "A password", false, Options{SecretsManagerEnable: true})
+	setResult, err = set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "secret", setResult.GetString("AWS_PASSWORD"), "secrets should be requested without the offline mode")
+
+	set = NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("AWS_PASSWORD", "default", "A password", false, Options{SecretsManagerEnable: true})
+	offline := set.flags.FlagSet.Bool(OfflineParameter, false, "An offline mode of the application")
+	setResult, err = set.ParseE([]string{"--OFFLINE"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.True(t, *offline, "should keep the flag of the application")
+	assert.Equal(t, "secret", setResult.GetString("AWS_PASSWORD"), "the flag of the application shouldn't enable the offline mode")
+}
+
+func TestParameterSet_AWSSessionError(t *testing.T) {
+	_ = os.Setenv("AWS_CA_BUNDLE", "not-existing-bundle.pem")
+	defer os.Unsetenv("AWS_CA_BUNDLE")
+
+	set := NewSet("test")
+	set.AddString("AWS_PASSWORD", "default", "A password", false, Options{SecretsManagerEnable: true})
+
+	_, err := set.ParseE([]string{})
+
+	assert.NotNil(t, err, "an error of the AWS session should be returned")
+	assert.Contains(t, err.Error(), "unable to create the AWS Secrets Manager client", "an error should describe the client")
+}
+
+func TestParameterSet_ConcurrentAWSClients(t *testing.T) {
+	first := newSecretsManagerStub(t, map[string]string{"AWS_PASSWORD": "first"}, 0)
+	defer first.Close()
+
+	second := newSecretsManagerStub(t, map[string]string{"AWS_PASSWORD": "second"}, 0)
+	defer second.Close()
+
+	sets := []*ParameterSet{NewSet("first"), NewSet("second")}
+	sets[0].SetAWSConfig(newAWSConfig(first.Server))
+	sets[1].SetAWSConfig(newAWSConfig(second.Server))
+
+	results := make([]Results, len(sets))
+	wg := sync.WaitGroup{}
+
+	for i, set := range sets {
+		set.AddString("AWS_PASSWORD", "", "A password", true, Options{SecretsManagerEnable: true})
+
+		wg.Add(1)
+		go func(i int, set *ParameterSet) {
+			defer wg.Done()
+			results[i], _ = set.ParseE([]string{})
+		}(i, set)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, "first", results[0].GetString("AWS_PASSWORD"), "should use the client of the first set")
+	assert.Equal(t, "second", results[1].GetString("AWS_PASSWORD"), "should use the client of the second set")
+}
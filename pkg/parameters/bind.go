@@ -0,0 +1,180 @@
+package parameters
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/barchart/common-go/pkg/configuration/database"
+)
+
+// Tags of struct fields used by Bind
+const (
+	paramTag    = "param"
+	defaultTag  = "default"
+	usageTag    = "usage"
+	requiredTag = "required"
+	secretTag   = "secret"
+)
+
+// secretTagStage is a value of the secret tag for a stage sensitive parameter
+const secretTagStage = "stage"
+
+// bindTypes maps types of struct fields to types of parameters
+var bindTypes = map[reflect.Type]string{
+	reflect.TypeOf(false):               boolType,
+	reflect.TypeOf(database.Database{}): databaseType,
+	reflect.TypeOf(time.Duration(0)):    durationType,
+	reflect.TypeOf(float64(0)):          float64Type,
+	reflect.TypeOf([]float64{}):         float64SliceType,
+	reflect.TypeOf(int64(0)):            int64Type,
+	reflect.TypeOf(0):                   intType,
+	reflect.TypeOf([]int{}):             intSliceType,
+	reflect.TypeOf(""):                  stringType,
+	reflect.TypeOf([]string{}):          stringSliceType,
+	reflect.TypeOf(map[string]string{}): stringMapType,
+	reflect.TypeOf(uint(0)):             uintType,
+	reflect.TypeOf(uint64(0)):           uint64Type,
+}
+
+// binding is a struct field filled by a parameter value after parsing
+type binding struct {
+	name  string
+	field reflect.Value
+}
+
+// Bind defines parameters for fields of the struct pointed to by v and fills the fields after the parsing.
+// A parameter is defined by the struct tags:
+//
+//	type Config struct {
+//		Host     string            `param:"DB_HOST" default:"localhost" usage:"A host of database"`
+//		Port     int               `param:"DB_PORT" usage:"A port of database" required:"true"`
+//		Database database.Database `param:"DATABASE" secret:"stage"`
+//		Cache    CacheConfig
+//	}
+//
+// The secret tag enables the AWS Secrets Manager: "true" or "stage" for a stage sensitive parameter.
+// Nested structs without the param tag are bound recursively, fields without the param tag are skipped.
+func (s *ParameterSet) Bind(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return errors.New("bind requires a pointer to a struct")
+	}
+
+	return s.bindStruct(value.Elem())
+}
+
+func (s *ParameterSet) bindStruct(value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := value.Type().Field(i)
+
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		name, ok := structField.Tag.Lookup(paramTag)
+		if !ok {
+			if _, isParameter := bindTypes[field.Type()]; !isParameter && field.Kind() == reflect.Struct {
+				if err := s.bindStruct(field); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if err := s.bindField(name, field, structField.Tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ParameterSet) bindField(name string, field reflect.Value, tag reflect.StructTag) error {
+	valueType, ok := bindTypes[field.Type()]
+	if !ok {
+		return fmt.Errorf("the %v parameter has unsupported type %v", name, field.Type())
+	}
+
+	value := field.Interface()
+	if defaultString, ok := tag.Lookup(defaultTag); ok {
+		defaultValue, err := convertString(defaultString, valueType)
+		if err != nil {
+			return fmt.Errorf("invalid default value %q of the %v parameter: %w", defaultString, name, err)
+		}
+		value = defaultValue
+	}
+
+	required := false
+	if requiredString, ok := tag.Lookup(requiredTag); ok {
+		parsed, err := strconv.ParseBool(requiredString)
+		if err != nil {
+			return fmt.Errorf("invalid required tag %q of the %v parameter", requiredString, name)
+		}
+		required = parsed
+	}
+
+	options := Options{}
+	switch secret := tag.Get(secretTag); secret {
+	case "", "false":
+	case "true":
+		options.SecretsManagerEnable = true
+	case secretTagStage:
+		options.SecretsManagerEnable = true
+		options.StageSensitive = true
+	default:
+		return fmt.Errorf("invalid secret tag %q of the %v parameter", secret, name)
+	}
+
+	usage := tag.Get(usageTag)
+
+	switch valueType {
+	case boolType:
+		s.AddBool(name, value.(bool), usage, required, options)
+	case databaseType:
+		s.AddDatabase(name, value.(database.Database), usage, required, options)
+	case durationType:
+		s.AddDuration(name, value.(time.Duration), usage, required, options)
+	case float64Type:
+		s.AddFloat64(name, value.(float64), usage, required, options)
+	case float64SliceType:
+		s.AddFloat64Slice(name, value.([]float64), usage, required, options)
+	case int64Type:
+		s.AddInt64(name, value.(int64), usage, required, options)
+	case intType:
+		s.AddInt(name, value.(int), usage, required, options)
+	case intSliceType:
+		s.AddIntSlice(name, value.([]int), usage, required, options)
+	case stringType:
+		s.AddString(name, value.(string), usage, required, options)
+	case stringSliceType:
+		s.AddStringSlice(name, value.([]string), usage, required, options)
+	case stringMapType:
+		s.AddStringMap(name, value.(map[string]string), usage, required, options)
+	case uintType:
+		s.AddUint(name, value.(uint), usage, required, options)
+	case uint64Type:
+		s.AddUint64(name, value.(uint64), usage, required, options)
+	}
+
+	s.bindings = append(s.bindings, binding{name: name, field: field})
+
+	return nil
+}
+
+// fillBindings sets values of bound struct fields from the results
+func (s *ParameterSet) fillBindings() {
+	for _, b := range s.bindings {
+		if value, ok := s.result[b.name]; ok && value != nil {
+			b.field.Set(reflect.ValueOf(value))
+		}
+	}
+}
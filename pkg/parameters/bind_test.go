@@ -0,0 +1,66 @@
+package parameters
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/barchart/common-go/pkg/configuration/database"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindCacheConfig struct {
+	TTL   time.Duration `param:"BIND_CACHE_TTL" default:"5m" usage:"A TTL of cache"`
+	Hosts []string      `param:"BIND_CACHE_HOSTS" default:"a,b"`
+}
+
+type bindConfig struct {
+	Host     string            `param:"BIND_HOST" default:"localhost" usage:"A host"`
+	Port     int               `param:"BIND_PORT" default:"5432" required:"true"`
+	Local    bool              `param:"BIND_LOCAL"`
+	Database database.Database `param:"BIND_DATABASE" secret:"stage"`
+	Cache    bindCacheConfig
+	Ignored  string `param:"-"`
+	internal string
+}
+
+func TestParameterSet_Bind(t *testing.T) {
+	_ = os.Setenv("BIND_PORT", "1234")
+	defer os.Unsetenv("BIND_PORT")
+
+	cfg := bindConfig{}
+	set := NewSet("test")
+
+	err := set.Bind(&cfg)
+	assert.Nil(t, err, "an error should be nil")
+
+	_, err = set.ParseE([]string{"--BIND_LOCAL", "--BIND_DATABASE=" + expectDatabase})
+	assert.Nil(t, err, "an error should be nil")
+
+	assert.Equal(t, "localhost", cfg.Host, "should fill a field by a default value")
+	assert.Equal(t, 1234, cfg.Port, "should fill a field by an env variable")
+	assert.Equal(t, true, cfg.Local, "should fill a field by a flag")
+	assert.Equal(t, expectedDatabase, cfg.Database, "should fill a database field")
+	assert.Equal(t, 5*time.Minute, cfg.Cache.TTL, "should fill a field of a nested struct")
+	assert.Equal(t, []string{"a", "b"}, cfg.Cache.Hosts, "should fill a slice field of a nested struct")
+
+	collection := set.GetCollection()
+	assert.Len(t, collection, 6, "should define parameters only for tagged fields")
+	assert.True(t, collection["BIND_PORT"].Required, "should define a required parameter")
+	assert.Equal(t, "A host", collection["BIND_HOST"].Usage, "should define an usage of parameter")
+	assert.Equal(t, Options{SecretsManagerEnable: true, StageSensitive: true}, collection["BIND_DATABASE"].Options, "should define options of parameter")
+}
+
+func TestParameterSet_BindErrors(t *testing.T) {
+	assert.NotNil(t, NewSet("test").Bind(bindConfig{}), "should return an error for a non-pointer")
+
+	unsupported := struct {
+		Value complex64 `param:"BIND_COMPLEX"`
+	}{}
+	assert.NotNil(t, NewSet("test").Bind(&unsupported), "should return an error for an unsupported type")
+
+	invalidDefault := struct {
+		Value int `param:"BIND_INT" default:"abc"`
+	}{}
+	assert.NotNil(t, NewSet("test").Bind(&invalidDefault), "should return an error for an invalid default value")
+}
@@ -1,18 +1,49 @@
 package parameters
 
+import "github.com/barchart/common-go/pkg/parameters/flags"
+
 const (
-	boolType     = "bool"
-	databaseType = "database"
-	float64Type  = "float64"
-	int64Type    = "int64"
-	intType      = "int"
-	stringType   = "string"
-	uintType     = "uint"
-	uint64Type   = "uint64"
+	boolType         = "bool"
+	databaseType     = "database"
+	durationType     = "duration"
+	float64Type      = "float64"
+	float64SliceType = "[]float64"
+	int64Type        = "int64"
+	intType          = "int"
+	intSliceType     = "[]int"
+	stringType       = "string"
+	stringSliceType  = "[]string"
+	stringMapType    = "map[string]string"
+	uintType         = "uint"
+	uint64Type       = "uint64"
 )
 
+// redactedValue replaces sensitive values in errors and outputs
+const redactedValue = flags.Redacted
+
 // AwsRegionSecrets is the constant name of AwsRegionSecrets flag or env variable
 const AwsRegionSecrets = "AWS-REGION-SECRETS"
 
+// defaultAWSRegion is the default region of AWS Secrets Manager and AWS SSM Parameter Store
+const defaultAWSRegion = "us-east-1"
+
+// OfflineParameter is the constant name of the offline mode flag or env variable
+const OfflineParameter = "OFFLINE"
+
+// ConfigParameter is the constant name of the config file flag or env variable
+const ConfigParameter = "CONFIG"
+
 // StageParameter is the constant name of stage flag or env variable
 const StageParameter = "STAGE"
+
+// Sources of parameter values
+const (
+	SourceFlag           = "flag"
+	SourceEnv            = "env"
+	SourceDotEnv         = "dotenv"
+	SourceFile           = "file"
+	SourceSecretsManager = "secretsmanager"
+	SourceParameterStore = "parameterstore"
+	SourceCustom         = "custom"
+	SourceDefault        = "default"
+)
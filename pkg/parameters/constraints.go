@@ -0,0 +1,169 @@
+package parameters
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Rules of constraints between parameters.
+const (
+	ConstraintRequireOneOf      = "require_one_of"
+	ConstraintMutuallyExclusive = "mutually_exclusive"
+	ConstraintRequiredIf        = "required_if"
+)
+
+// Predicate is a condition of the RequiredIf constraint. Description is used by the usage output and errors,
+// e.g. "TLS_CERT is provided".
+type Predicate struct {
+	Description string
+	Match       func(results Results) bool
+	// name is a name of the parameter of predicates of IsProvided and Equals
+	name string
+	// provided is true if the parameter must be provided
+	provided bool
+}
+
+// IsProvided returns a predicate which matches if a value of the parameter was provided by a source.
+func IsProvided(name string) Predicate {
+	return Predicate{
+		Description: fmt.Sprintf("%v is provided", name),
+		name:        name,
+		provided:    true,
+	}
+}
+
+// matches returns true if the predicate matches values of the set
+func (p Predicate) matches(s *ParameterSet, results Results) bool {
+	if p.provided {
+		return s.Provided(p.name)
+	}
+
+	return p.Match(results)
+}
+
+// Equals returns a predicate which matches if a value of the parameter equals to the value.
+func Equals(name string, value interface{}) Predicate {
+	return Predicate{
+		Description: fmt.Sprintf("%v is %v", name, value),
+		name:        name,
+		Match: func(results Results) bool {
+			return reflect.DeepEqual(results[name], value)
+		},
+	}
+}
+
+// Constraint is a rule between parameters of the set, which is checked by Parse after values of all parameters are resolved.
+// Rule is ConstraintRequireOneOf, ConstraintMutuallyExclusive or ConstraintRequiredIf, Names are names of parameters
+// of the rule and Predicate is a condition of the ConstraintRequiredIf rule.
+type Constraint struct {
+	Rule      string
+	Names     []string
+	Predicate Predicate
+}
+
+// String returns a description of the constraint, e.g. "one of DATABASE, HOST is required".
+func (c Constraint) String() string {
+	switch c.Rule {
+	case ConstraintRequireOneOf:
+		return fmt.Sprintf("one of %v is required", strings.Join(c.Names, ", "))
+	case ConstraintMutuallyExclusive:
+		return fmt.Sprintf("only one of %v can be provided", strings.Join(c.Names, ", "))
+	case ConstraintRequiredIf:
+		return fmt.Sprintf("%v is required if %v", strings.Join(c.Names, ", "), c.Predicate.Description)
+	}
+
+	return c.Rule
+}
+
+// ConstraintError is returned by ParseE if values of parameters don't satisfy constraints of the set.
+type ConstraintError struct {
+	Constraints []Constraint
+}
+
+func (e *ConstraintError) Error() string {
+	messages := make([]string, 0, len(e.Constraints))
+	for _, constraint := range e.Constraints {
+		messages = append(messages, constraint.String()+".")
+	}
+
+	return fmt.Sprintf("unsatisfied constraints: [ %v ]", strings.Join(messages, " "))
+}
+
+// RequireOneOf adds a constraint which requires a value of at least one of the parameters.
+// The parameters must be added before the constraint.
+func (s *ParameterSet) RequireOneOf(names ...string) {
+	s.addConstraint(Constraint{Rule: ConstraintRequireOneOf, Names: names})
+}
+
+// MutuallyExclusive adds a constraint which allows a value of at most one of the parameters.
+// The parameters must be added before the constraint.
+func (s *ParameterSet) MutuallyExclusive(names ...string) {
+	s.addConstraint(Constraint{Rule: ConstraintMutuallyExclusive, Names: names})
+}
+
+// RequiredIf adds a constraint which requires a value of the parameter if the predicate matches, e.g.
+// RequiredIf("TLS_KEY", IsProvided("TLS_CERT")). The parameters must be added before the constraint.
+func (s *ParameterSet) RequiredIf(name string, predicate Predicate) {
+	if predicate.Match == nil && !predicate.provided {
+		log.Panicf("the predicate of the %v parameter doesn't have a match function", name)
+	}
+
+	s.addConstraint(Constraint{Rule: ConstraintRequiredIf, Names: []string{name}, Predicate: predicate})
+}
+
+// addConstraint adds the constraint to the set, it panics if the constraint refers to a parameter which isn't defined
+func (s *ParameterSet) addConstraint(constraint Constraint) {
+	names := constraint.Names
+	if constraint.Predicate.name != "" {
+		names = append(names[:len(names):len(names)], constraint.Predicate.name)
+	}
+
+	for _, name := range names {
+		if _, ok := s.collection[name]; !ok {
+			log.Panicf("the constraint \"%v\" refers to the undefined %v parameter", constraint, name)
+		}
+	}
+
+	s.constraints = append(s.constraints, constraint)
+}
+
+// GetConstraints returns constraints of the set in the order they were added.
+func (s *ParameterSet) GetConstraints() []Constraint {
+	constraints := make([]Constraint, len(s.constraints))
+	copy(constraints, s.constraints)
+
+	return constraints
+}
+
+// checkConstraints returns constraints which aren't satisfied by the results
+func (s *ParameterSet) checkConstraints(results Results) []Constraint {
+	unsatisfied := make([]Constraint, 0)
+
+	for _, constraint := range s.constraints {
+		provided := 0
+
+		for _, name := range constraint.Names {
+			if s.Provided(name) {
+				provided++
+			}
+		}
+
+		switch constraint.Rule {
+		case ConstraintRequireOneOf:
+			if provided == 0 {
+				unsatisfied = append(unsatisfied, constraint)
+			}
+		case ConstraintMutuallyExclusive:
+			if provided > 1 {
+				unsatisfied = append(unsatisfied, constraint)
+			}
+		case ConstraintRequiredIf:
+			if provided == 0 && constraint.Predicate.matches(s, results) {
+				unsatisfied = append(unsatisfied, constraint)
+			}
+		}
+	}
+
+	return unsatisfied
+}
@@ -0,0 +1,74 @@
+package parameters
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newConstraintsSet() *ParameterSet {
+	set := NewSet("test")
+	set.AddString("DATABASE", "", "A database URL", false)
+	set.AddString("HOST", "", "A host", false)
+	set.AddInt("PORT", 5432, "A port", false)
+	set.AddString("TLS_CERT", "", "A TLS certificate", false)
+	set.AddString("TLS_KEY", "", "A TLS key", false)
+	set.AddString("MODE", "plain", "A mode", false)
+
+	set.RequireOneOf("DATABASE", "HOST")
+	set.MutuallyExclusive("DATABASE", "HOST")
+	set.RequiredIf("PORT", IsProvided("HOST"))
+	set.RequiredIf("TLS_KEY", IsProvided("TLS_CERT"))
+	set.RequiredIf("TLS_CERT", Equals("MODE", "tls"))
+
+	return set
+}
+
+func TestParameterSet_Constraints(t *testing.T) {
+	set := newConstraintsSet()
+	setResult, err := set.ParseE([]string{"--HOST=localhost", "--PORT=5433", "--TLS_CERT=cert", "--TLS_KEY=key", "--MODE=tls"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "localhost", setResult.GetString("HOST"), "should return a value of the parameter")
+	assert.True(t, set.Provided("PORT"), "should return true for the provided parameter")
+	assert.False(t, set.Provided("DATABASE"), "should return false for the parameter with the default value")
+}
+
+func TestParameterSet_ConstraintsUnsatisfied(t *testing.T) {
+	_, err := newConstraintsSet().ParseE([]string{"--MODE=tls"})
+
+	var constraintErr *ConstraintError
+	assert.True(t, errors.As(err, &constraintErr), "should return a ConstraintError")
+	assert.Equal(t, "unsatisfied constraints: [ one of DATABASE, HOST is required. TLS_CERT is required if MODE is tls. ]", err.Error(), "should aggregate unsatisfied constraints")
+
+	_, err = newConstraintsSet().ParseE([]string{"--DATABASE=url", "--HOST=localhost", "--TLS_CERT=cert"})
+
+	assert.Equal(t, "unsatisfied constraints: [ only one of DATABASE, HOST can be provided. PORT is required if HOST is provided. TLS_KEY is required if TLS_CERT is provided. ]", err.Error(), "should aggregate unsatisfied constraints")
+}
+
+func TestParameterSet_ConstraintsUndefinedParameter(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("HOST", "", "A host", false)
+
+	assert.Panics(t, func() {
+		set.RequireOneOf("HOST", "DATABASE")
+	}, "should panic if the constraint refers to the undefined parameter")
+
+	assert.Panics(t, func() {
+		set.RequiredIf("HOST", IsProvided("DATABASE"))
+	}, "should panic if the predicate refers to the undefined parameter")
+
+	assert.Empty(t, set.GetConstraints(), "shouldn't add invalid constraints")
+
+	_, err := set.ParseE([]string{})
+	assert.Nil(t, err, "an error should be nil")
+}
+
+func TestParameterSet_GetConstraints(t *testing.T) {
+	constraints := newConstraintsSet().GetConstraints()
+
+	assert.Len(t, constraints, 5, "should return all constraints")
+	assert.Equal(t, ConstraintRequireOneOf, constraints[0].Rule, "should return constraints in order")
+	assert.Equal(t, "TLS_KEY is required if TLS_CERT is provided", constraints[3].String(), "should describe the constraint")
+}
@@ -0,0 +1,151 @@
+package parameters
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// stagePlaceholder is replaced by a lower case value of the STAGE parameter in names of dotenv files
+const stagePlaceholder = "{stage}"
+
+// dotenvValue is a value of the variable loaded from a dotenv file
+type dotenvValue struct {
+	value string
+	file  string
+}
+
+// loadDotEnvFile reads a dotenv file. Each variable is passed to the set function, so following lines can refer to it.
+// A missing file is skipped.
+func loadDotEnvFile(path string, lookup func(string) (string, bool), set func(key, value string)) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := parseDotEnv(string(data), lookup, set); err != nil {
+		return fmt.Errorf("%v: %w", path, err)
+	}
+
+	return nil
+}
+
+// parseDotEnv parses variables in the KEY=value form. Lines starting with # are comments, the export prefix is allowed.
+// A double-quoted value can span multiple lines, supports escape sequences (\n, \t, \", \\, \$) and ${VAR} expansion.
+// A single-quoted value is literal. An unquoted value is trimmed, supports ${VAR} expansion and inline # comments.
+func parseDotEnv(data string, lookup func(string) (string, bool), set func(key, value string)) error {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		separator := strings.Index(line, "=")
+		if separator < 1 {
+			return fmt.Errorf("line %v: invalid format", i+1)
+		}
+
+		key := strings.TrimSpace(line[:separator])
+		raw := strings.TrimSpace(line[separator+1:])
+		lineNumber := i + 1
+
+		var value string
+
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			end := closingQuote(raw)
+			for end < 0 && i+1 < len(lines) {
+				i++
+				raw += "\n" + lines[i]
+				end = closingQuote(raw)
+			}
+			if end < 0 {
+				return fmt.Errorf("line %v: unterminated quoted value", lineNumber)
+			}
+			value = unescape(raw[1:end], lookup)
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				return fmt.Errorf("line %v: unterminated quoted value", lineNumber)
+			}
+			value = raw[1 : end+1]
+		default:
+			if comment := strings.Index(raw, " #"); comment >= 0 {
+				raw = raw[:comment]
+			}
+			value = os.Expand(strings.TrimSpace(raw), expandFunc(lookup))
+		}
+
+		set(key, value)
+	}
+
+	return nil
+}
+
+// closingQuote returns an index of the closing double quote of the value or -1
+func closingQuote(raw string) int {
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unescape replaces escape sequences and expands variables of a double-quoted value
+func unescape(raw string, lookup func(string) (string, bool)) string {
+	var buf strings.Builder
+	var chunk strings.Builder
+
+	flush := func() {
+		buf.WriteString(os.Expand(chunk.String(), expandFunc(lookup)))
+		chunk.Reset()
+	}
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 == len(raw) {
+			chunk.WriteByte(raw[i])
+			continue
+		}
+
+		i++
+
+		switch raw[i] {
+		case 'n':
+			chunk.WriteByte('\n')
+		case 'r':
+			chunk.WriteByte('\r')
+		case 't':
+			chunk.WriteByte('\t')
+		case '$':
+			flush()
+			buf.WriteByte('$')
+		default:
+			chunk.WriteByte(raw[i])
+		}
+	}
+
+	flush()
+
+	return buf.String()
+}
+
+// expandFunc returns a mapping function of os.Expand. An undefined variable is replaced by an empty string.
+func expandFunc(lookup func(string) (string, bool)) func(string) string {
+	return func(name string) string {
+		value, _ := lookup(name)
+		return value
+	}
+}
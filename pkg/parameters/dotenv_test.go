@@ -0,0 +1,90 @@
+package parameters
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dotenv = `
+# comment
+export DOTENV_PLAIN=plain value # inline comment
+DOTENV_SINGLE='single ${DOTENV_PLAIN} # not a comment'
+DOTENV_DOUBLE="double \"${DOTENV_PLAIN}\"\n\$DOTENV_PLAIN"
+DOTENV_MULTILINE="first
+second"
+DOTENV_EXPAND=${DOTENV_REAL}/$DOTENV_PLAIN
+DOTENV_EMPTY=
+`
+
+func TestParseDotEnv(t *testing.T) {
+	_ = os.Setenv("DOTENV_REAL", "real")
+	defer os.Unsetenv("DOTENV_REAL")
+
+	values := map[string]string{}
+	lookup := func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := values[name]
+		return value, ok
+	}
+
+	err := parseDotEnv(dotenv, lookup, func(key, value string) { values[key] = value })
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, map[string]string{
+		"DOTENV_PLAIN":     "plain value",
+		"DOTENV_SINGLE":    "single ${DOTENV_PLAIN} # not a comment",
+		"DOTENV_DOUBLE":    "double \"plain value\"\n$DOTENV_PLAIN",
+		"DOTENV_MULTILINE": "first\nsecond",
+		"DOTENV_EXPAND":    "real/plain value",
+		"DOTENV_EMPTY":     "",
+	}, values, "should parse all variables")
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	lookup := func(string) (string, bool) { return "", false }
+	set := func(string, string) {}
+
+	assert.NotNil(t, parseDotEnv("INVALID", lookup, set), "should return an error for a line without =")
+	assert.NotNil(t, parseDotEnv("KEY=\"unterminated", lookup, set), "should return an error for an unterminated value")
+	assert.NotNil(t, parseDotEnv("KEY='unterminated", lookup, set), "should return an error for an unterminated value")
+}
+
+func TestParameterSet_SetDotEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, data string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(".env", "STAGE=dev\nDOTENV_HOST=env.example.com\nDOTENV_PORT=1\nDOTENV_REAL_PORT=1\n")
+	writeFile(".env.dev", "DOTENV_PORT=2\n")
+
+	_ = os.Setenv("DOTENV_REAL_PORT", "3")
+	defer os.Unsetenv("DOTENV_REAL_PORT")
+
+	set := NewSet("test")
+	set.SetDotEnvFiles(filepath.Join(dir, ".env"), filepath.Join(dir, ".env.{stage}"), filepath.Join(dir, ".env.local"))
+	set.AddString(StageParameter, "", "A stage", false)
+	set.AddString("DOTENV_HOST", "", "A host", false)
+	set.AddInt("DOTENV_PORT", 0, "A port", false)
+	set.AddInt("DOTENV_REAL_PORT", 0, "A port", false)
+
+	setResult, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "dev", setResult.GetString(StageParameter), "should return a stage from the dotenv file")
+	assert.Equal(t, "env.example.com", setResult.GetString("DOTENV_HOST"), "should return a value from the dotenv file")
+	assert.Equal(t, 2, setResult.GetInt("DOTENV_PORT"), "should return a value from the stage dotenv file")
+	assert.Equal(t, 3, setResult.GetInt("DOTENV_REAL_PORT"), "a real env variable should override the dotenv file")
+
+	_, file, ok := set.LookupDotEnv("DOTENV_PORT")
+	assert.True(t, ok, "should find a variable loaded from the dotenv file")
+	assert.Equal(t, filepath.Join(dir, ".env.dev"), file, "should return a path of the dotenv file")
+}
@@ -0,0 +1,40 @@
+package parameters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrFlagsParsed is returned by ParseE if flags of the set have already been parsed outside the parameters package.
+var ErrFlagsParsed = errors.New("flags have already parsed")
+
+// ErrNoParameters is returned by ParseE if no parameters were added to the set.
+var ErrNoParameters = errors.New("parameters wasn't added")
+
+// MissingParametersError is returned by ParseE if required parameters weren't provided.
+type MissingParametersError struct {
+	Names []string
+}
+
+func (e *MissingParametersError) Error() string {
+	return fmt.Sprintf("missing required parameters: [ %v ]", strings.Join(e.Names, ","))
+}
+
+// InvalidValueError is returned by ParseE if a value of the parameter can't be converted to the parameter type.
+// Source is a name of the source, e.g. SourceFlag, SourceEnv, SourceDotEnv, SourceFile or SourceSecretsManager.
+// Raw values from the AWS Secrets Manager and AWS SSM Parameter Store are redacted.
+type InvalidValueError struct {
+	Name   string
+	Source string
+	Raw    string
+	Err    error
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid value %q of the %v parameter from %v: %v", e.Raw, e.Name, e.Source, e.Err)
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
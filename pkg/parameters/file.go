@@ -0,0 +1,59 @@
+package parameters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadConfigFile reads a JSON or YAML file and returns raw values keyed by parameter names.
+// Nested objects and arrays are kept as JSON, so they are converted the same way as values of environment variables.
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		err = fmt.Errorf("unsupported format of the config file %v", path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(values))
+
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			result[key] = v
+		case map[string]interface{}, []interface{}:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of the %v parameter in the config file %v: %w", key, path, err)
+			}
+			result[key] = string(raw)
+		default:
+			result[key] = fmt.Sprint(v)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package parameters
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/barchart/common-go/pkg/configuration/database"
+	"github.com/stretchr/testify/assert"
+)
+
+const configYAML = `
+FILE_HOST: example.com
+FILE_PORT: 1234
+FILE_TIMEOUT: 1m30s
+FILE_HOSTS:
+  - a.example.com
+  - b.example.com
+FILE_DATABASE:
+  provider: mysql
+  host: https://example.com
+  port: 54321
+  database: database
+  username: user
+  password: password
+`
+
+const configJSON = `{"FILE_HOST": "example.com", "FILE_PORT": 1234, "FILE_HEADERS": {"a": "1"}}`
+
+func writeConfigFile(t *testing.T, name string, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestParameterSet_ConfigFileYAML(t *testing.T) {
+	_ = os.Setenv("FILE_PORT", "4321")
+	defer os.Unsetenv("FILE_PORT")
+
+	set := NewSet("test")
+	set.AddString("FILE_HOST", "", "A host", false)
+	set.AddInt("FILE_PORT", 0, "A port", false)
+	set.AddDuration("FILE_TIMEOUT", 0, "A timeout", false)
+	set.AddStringSlice("FILE_HOSTS", nil, "A list of hosts", false)
+	set.AddDatabase("FILE_DATABASE", database.Database{}, "A database", false)
+	set.AddString("FILE_DEFAULT", "default", "A default value", false)
+
+	setResult, err := set.ParseE([]string{"--CONFIG=" + writeConfigFile(t, "app.yaml", configYAML)})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "example.com", setResult.GetString("FILE_HOST"), "should return a value from the config file")
+	assert.Equal(t, 4321, setResult.GetInt("FILE_PORT"), "an env variable should override the config file")
+	assert.Equal(t, 90*time.Second, setResult.GetDuration("FILE_TIMEOUT"), "should return a duration from the config file")
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, setResult.GetStringSlice("FILE_HOSTS"), "should return a list from the config file")
+	assert.Equal(t, expectedDatabase, setResult.GetDatabase("FILE_DATABASE"), "should return a database from a nested map")
+	assert.Equal(t, "default", setResult.GetString("FILE_DEFAULT"), "should return a default value")
+}
+
+func TestParameterSet_SetConfigFileJSON(t *testing.T) {
+	set := NewSet("test")
+	set.SetConfigFile(writeConfigFile(t, "app.json", configJSON))
+	set.AddString("FILE_HOST", "", "A host", false)
+	set.AddInt("FILE_PORT", 0, "A port", false)
+	set.AddStringMap("FILE_HEADERS", nil, "Headers", false)
+
+	setResult, err := set.ParseE([]string{"--FILE_HOST=flag.example.com"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "flag.example.com", setResult.GetString("FILE_HOST"), "a flag should override the config file")
+	assert.Equal(t, 1234, setResult.GetInt("FILE_PORT"), "should return a value from the config file")
+	assert.Equal(t, map[string]string{"a": "1"}, setResult.GetStringMap("FILE_HEADERS"), "should return a map from the config file")
+}
+
+func TestParameterSet_ConfigFileErrors(t *testing.T) {
+	set := NewSet("test")
+	set.SetConfigFile(filepath.Join(t.TempDir(), "not-exist.yaml"))
+	set.AddString("FILE_HOST", "", "A host", false)
+
+	_, err := set.ParseE([]string{})
+	assert.True(t, errors.Is(err, os.ErrNotExist), "should return an error for a missing file")
+
+	set = NewSet("test")
+	set.SetConfigFile(writeConfigFile(t, "app.json", `{"FILE_PORT": "abc"}`))
+	set.AddInt("FILE_PORT", 0, "A port", false)
+
+	_, err = set.ParseE([]string{})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, SourceFile, invalidErr.Source, "an error should contain source of the value")
+}
+
+func TestParameterSet_ConfigFileApplicationFlag(t *testing.T) {
+	set := NewSet("test")
+	set.SetConfigFile(writeConfigFile(t, "app.json", configJSON))
+	set.AddInt("FILE_PORT", 0, "A port", false)
+
+	config := set.flags.FlagSet.String(ConfigParameter, "", "A config of the application")
+
+	setResult, err := set.ParseE([]string{"--CONFIG=app.ini"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "app.ini", *config, "should keep the flag of the application")
+	assert.Equal(t, 1234, setResult.GetInt("FILE_PORT"), "should use the config file set by SetConfigFile")
+}
@@ -10,7 +10,7 @@ type BoolValue struct {
 func (b *BoolValue) Set(s string) error {
 	v, err := strconv.ParseBool(s)
 	if err != nil {
-		err = errParse
+		err = ErrParse
 	} else {
 		b.set = true
 	}
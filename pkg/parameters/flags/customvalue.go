@@ -0,0 +1,31 @@
+package flags
+
+type CustomValue struct {
+	set    bool
+	value  interface{}
+	parse  func(string) (interface{}, error)
+	format func(interface{}) string
+}
+
+func (c *CustomValue) Set(s string) error {
+	v, err := c.parse(s)
+	if err != nil {
+		return err
+	}
+	c.value = v
+	c.set = true
+
+	return nil
+}
+
+func (c *CustomValue) Get() interface{} { return c.value }
+
+func (c *CustomValue) String() string {
+	if c.format == nil {
+		return ""
+	}
+
+	return c.format(c.value)
+}
+
+func (c *CustomValue) IsSet() bool { return c.set }
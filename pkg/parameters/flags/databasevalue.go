@@ -15,7 +15,7 @@ func (db *DatabaseValue) Set(s string) error {
 	v := database.Database{}
 	err := json.Unmarshal([]byte(s), &v)
 	if err != nil {
-		err = errParse
+		err = ErrParse
 	} else {
 		db.set = true
 	}
@@ -25,13 +25,25 @@ func (db *DatabaseValue) Set(s string) error {
 
 func (db *DatabaseValue) Get() interface{} { return db.value }
 
-func (db *DatabaseValue) String() string {
-	value, err := json.Marshal(db.value)
+func (db *DatabaseValue) String() string { return FormatDatabase(db.value) }
+
+func (db *DatabaseValue) IsSet() bool { return db.set }
+
+// RedactDatabase returns a copy of the database with the password replaced by Redacted.
+func RedactDatabase(value database.Database) database.Database {
+	if value.Password != "" {
+		value.Password = Redacted
+	}
+
+	return value
+}
+
+// FormatDatabase returns JSON of the database. The password is always redacted.
+func FormatDatabase(value database.Database) string {
+	formatted, err := json.Marshal(RedactDatabase(value))
 	if err != nil {
 		return ""
-	} else {
-		return string(value)
 	}
-}
 
-func (db *DatabaseValue) IsSet() bool { return db.set }
+	return string(formatted)
+}
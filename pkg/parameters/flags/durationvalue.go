@@ -0,0 +1,25 @@
+package flags
+
+import "time"
+
+type DurationValue struct {
+	set   bool
+	value time.Duration
+}
+
+func (d *DurationValue) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		err = ErrParse
+	} else {
+		d.set = true
+	}
+	d.value = v
+	return err
+}
+
+func (d *DurationValue) Get() interface{} { return d.value }
+
+func (d *DurationValue) String() string { return d.value.String() }
+
+func (d *DurationValue) IsSet() bool { return d.set }
@@ -12,107 +12,348 @@ package flags
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/barchart/common-go/pkg/configuration/database"
 )
 
+// FlagSet is a set of defined flags. It wraps flag.FlagSet, so flags defined by the package types
+// can be registered in a set which is independent of the flag.CommandLine.
+type FlagSet struct {
+	*flag.FlagSet
+}
+
+// CommandLine is the default set of flags, backed by flag.CommandLine.
+var CommandLine = &FlagSet{FlagSet: flag.CommandLine}
+
+// NewFlagSet returns a new, empty flag set with the specified name and error handling property.
+func NewFlagSet(name string, errorHandling flag.ErrorHandling) *FlagSet {
+	return &FlagSet{FlagSet: flag.NewFlagSet(name, errorHandling)}
+}
+
+// Parse parses flag definitions from the argument list, which should not include the command name.
+// If a flag's value fails to parse, the returned error is a *ValueError.
+func (f *FlagSet) Parse(arguments []string) error {
+	var invalid *ValueError
+
+	f.VisitAll(func(flg *flag.Flag) {
+		flg.Value = &recorder{Value: flg.Value, name: flg.Name, invalid: &invalid}
+	})
+	defer f.VisitAll(func(flg *flag.Flag) {
+		if r, ok := flg.Value.(*recorder); ok {
+			flg.Value = r.Value
+		}
+	})
+
+	err := f.FlagSet.Parse(arguments)
+	if err != nil && invalid != nil {
+		return invalid
+	}
+
+	return err
+}
+
+// Sensitive marks the flag as sensitive. The flag value is wrapped by SensitiveValue and its default value is redacted.
+func (f *FlagSet) Sensitive(name string) {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return
+	}
+
+	if _, ok := flg.Value.(*SensitiveValue); !ok {
+		flg.Value = &SensitiveValue{Value: flg.Value}
+	}
+
+	if flg.DefValue != "" {
+		flg.DefValue = Redacted
+	}
+}
+
 // Bool defines a bool flag with specified name, default value, and usage string.
-func Bool(name string, value bool, usage string) {
+func (f *FlagSet) Bool(name string, value bool, usage string) {
 	v := BoolValue{
 		set:   false,
 		value: value,
 	}
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
 }
 
-// Bool defines a database flag with specified name, default value, and usage string.
-func Database(name string, value database.Database, usage string) {
+// Custom defines a flag of a custom type with specified name, default value, and usage string.
+// The parse function converts a string to the value and the format function converts the value to a string.
+func (f *FlagSet) Custom(name string, value interface{}, usage string, parse func(string) (interface{}, error), format func(interface{}) string) {
+	v := CustomValue{
+		set:    false,
+		value:  value,
+		parse:  parse,
+		format: format,
+	}
+
+	f.Var(&v, name, usage)
+}
+
+// Database defines a database flag with specified name, default value, and usage string.
+func (f *FlagSet) Database(name string, value database.Database, usage string) {
 	v := DatabaseValue{
 		set:   false,
 		value: value,
 	}
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
+}
+
+// Duration defines a time.Duration flag with specified name, default value, and usage string.
+func (f *FlagSet) Duration(name string, value time.Duration, usage string) {
+	v := DurationValue{
+		set:   false,
+		value: value,
+	}
+
+	f.Var(&v, name, usage)
 }
 
 // Float64 defines a float64 flag with specified name, default value, and usage string.
-func Float64(name string, value float64, usage string) {
+func (f *FlagSet) Float64(name string, value float64, usage string) {
 	v := Float64Value{
 		set:   false,
 		value: value,
 	}
 
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
+}
+
+// Float64Slice defines a []float64 flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated values.
+func (f *FlagSet) Float64Slice(name string, value []float64, usage string) {
+	v := Float64SliceValue{
+		set:   false,
+		value: value,
+	}
+
+	f.Var(&v, name, usage)
 }
 
 // Int defines a int flag with specified name, default value, and usage string.
-func Int(name string, value int, usage string) {
+func (f *FlagSet) Int(name string, value int, usage string) {
 	v := IntValue{
 		set:   false,
 		value: value,
 	}
 
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
 }
 
 // Int64 defines a int64 flag with specified name, default value, and usage string.
-func Int64(name string, value int64, usage string) {
+func (f *FlagSet) Int64(name string, value int64, usage string) {
 	v := Int64Value{
 		set:   false,
 		value: value,
 	}
 
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
+}
+
+// IntSlice defines a []int flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated values.
+func (f *FlagSet) IntSlice(name string, value []int, usage string) {
+	v := IntSliceValue{
+		set:   false,
+		value: value,
+	}
+
+	f.Var(&v, name, usage)
 }
 
 // String defines a string flag with specified name, default value, and usage string.
-func String(name string, value string, usage string) {
+func (f *FlagSet) String(name string, value string, usage string) {
 	v := StringValue{
 		set:   false,
 		value: value,
 	}
 
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
+}
+
+// StringSlice defines a []string flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated values.
+func (f *FlagSet) StringSlice(name string, value []string, usage string) {
+	v := StringSliceValue{
+		set:   false,
+		value: value,
+	}
+
+	f.Var(&v, name, usage)
+}
+
+// StringMap defines a map[string]string flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated key=value pairs.
+func (f *FlagSet) StringMap(name string, value map[string]string, usage string) {
+	v := StringMapValue{
+		set:   false,
+		value: value,
+	}
+
+	f.Var(&v, name, usage)
 }
 
 // Uint defines a uint flag with specified name, default value, and usage string.
-func Uint(name string, value uint, usage string) {
+func (f *FlagSet) Uint(name string, value uint, usage string) {
 	v := UintValue{
 		set:   false,
 		value: value,
 	}
 
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
 }
 
 // Uint64 defines a uint64 flag with specified name, default value, and usage string.
-func Uint64(name string, value uint64, usage string) {
+func (f *FlagSet) Uint64(name string, value uint64, usage string) {
 	v := Uint64Value{
 		set:   false,
 		value: value,
 	}
 
-	flag.Var(&v, name, usage)
+	f.Var(&v, name, usage)
 }
 
-// errParse is returned by Set if a flag's value fails to parse, such as with an invalid integer for Int.
-// It then gets wrapped through failf to provide more information.
-var errParse = errors.New("parse error")
+// Bool defines a bool flag with specified name, default value, and usage string.
+func Bool(name string, value bool, usage string) {
+	CommandLine.Bool(name, value, usage)
+}
+
+// Custom defines a flag of a custom type with specified name, default value, and usage string.
+// The parse function converts a string to the value and the format function converts the value to a string.
+func Custom(name string, value interface{}, usage string, parse func(string) (interface{}, error), format func(interface{}) string) {
+	CommandLine.Custom(name, value, usage, parse, format)
+}
+
+// Database defines a database flag with specified name, default value, and usage string.
+func Database(name string, value database.Database, usage string) {
+	CommandLine.Database(name, value, usage)
+}
+
+// Duration defines a time.Duration flag with specified name, default value, and usage string.
+func Duration(name string, value time.Duration, usage string) {
+	CommandLine.Duration(name, value, usage)
+}
+
+// Float64 defines a float64 flag with specified name, default value, and usage string.
+func Float64(name string, value float64, usage string) {
+	CommandLine.Float64(name, value, usage)
+}
+
+// Float64Slice defines a []float64 flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated values.
+func Float64Slice(name string, value []float64, usage string) {
+	CommandLine.Float64Slice(name, value, usage)
+}
+
+// Int defines a int flag with specified name, default value, and usage string.
+func Int(name string, value int, usage string) {
+	CommandLine.Int(name, value, usage)
+}
+
+// Int64 defines a int64 flag with specified name, default value, and usage string.
+func Int64(name string, value int64, usage string) {
+	CommandLine.Int64(name, value, usage)
+}
+
+// IntSlice defines a []int flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated values.
+func IntSlice(name string, value []int, usage string) {
+	CommandLine.IntSlice(name, value, usage)
+}
+
+// String defines a string flag with specified name, default value, and usage string.
+func String(name string, value string, usage string) {
+	CommandLine.String(name, value, usage)
+}
+
+// StringSlice defines a []string flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated values.
+func StringSlice(name string, value []string, usage string) {
+	CommandLine.StringSlice(name, value, usage)
+}
 
-// errRange is returned by Set if a flag's value is out of range.
-// It then gets wrapped through failf to provide more information.
-var errRange = errors.New("value out of range")
+// StringMap defines a map[string]string flag with specified name, default value, and usage string.
+// The flag can be repeated and accepts comma-separated key=value pairs.
+func StringMap(name string, value map[string]string, usage string) {
+	CommandLine.StringMap(name, value, usage)
+}
+
+// Uint defines a uint flag with specified name, default value, and usage string.
+func Uint(name string, value uint, usage string) {
+	CommandLine.Uint(name, value, usage)
+}
+
+// Uint64 defines a uint64 flag with specified name, default value, and usage string.
+func Uint64(name string, value uint64, usage string) {
+	CommandLine.Uint64(name, value, usage)
+}
+
+// ErrParse is returned by Set if a flag's value fails to parse, such as with an invalid integer for Int.
+// It then gets wrapped through ValueError to provide more information.
+var ErrParse = errors.New("parse error")
+
+// ErrRange is returned by Set if a flag's value is out of range.
+// It then gets wrapped through ValueError to provide more information.
+var ErrRange = errors.New("value out of range")
+
+// ValueError is returned by FlagSet.Parse if a flag's value fails to parse.
+type ValueError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for flag -%v: %v", e.Value, e.Name, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// recorder wraps a flag value during the parsing and keeps the first error returned by Set.
+type recorder struct {
+	flag.Value
+	name    string
+	invalid **ValueError
+}
+
+func (r *recorder) Set(s string) error {
+	err := r.Value.Set(s)
+	if err != nil && *r.invalid == nil {
+		*r.invalid = &ValueError{Name: r.name, Value: s, Err: err}
+	}
+
+	return err
+}
+
+func (r *recorder) String() string {
+	if r.Value == nil {
+		return ""
+	}
+
+	return r.Value.String()
+}
+
+func (r *recorder) IsBoolFlag() bool {
+	b, ok := r.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
 
-func numError(err error) error {
+// NumError converts an error of the strconv package to ErrParse or ErrRange.
+func NumError(err error) error {
 	ne, ok := err.(*strconv.NumError)
 	if !ok {
 		return err
 	}
 	if ne.Err == strconv.ErrSyntax {
-		return errParse
+		return ErrParse
 	}
 	if ne.Err == strconv.ErrRange {
-		return errRange
+		return ErrRange
 	}
 	return err
 }
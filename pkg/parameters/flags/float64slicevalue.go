@@ -0,0 +1,52 @@
+package flags
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Float64SliceValue struct {
+	set   bool
+	value []float64
+}
+
+// Set appends comma-separated values to the slice. The first call replaces the default value.
+func (f *Float64SliceValue) Set(s string) error {
+	values, err := ParseFloat64Slice(s)
+	if err != nil {
+		return err
+	}
+	if !f.set {
+		f.value = nil
+	}
+	f.value = append(f.value, values...)
+	f.set = true
+
+	return nil
+}
+
+func (f *Float64SliceValue) Get() interface{} { return f.value }
+
+func (f *Float64SliceValue) String() string {
+	values := make([]string, len(f.value))
+	for index, v := range f.value {
+		values[index] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *Float64SliceValue) IsSet() bool { return f.set }
+
+// ParseFloat64Slice parses comma-separated float64 values, spaces around values are trimmed.
+func ParseFloat64Slice(s string) ([]float64, error) {
+	values := make([]float64, 0, 1)
+	for _, str := range ParseStringSlice(s) {
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, NumError(err)
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
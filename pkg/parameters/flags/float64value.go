@@ -10,7 +10,7 @@ type Float64Value struct {
 func (f *Float64Value) Set(s string) error {
 	v, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		err = numError(err)
+		err = NumError(err)
 	}
 	f.value = v
 	f.set = true
@@ -8,10 +8,7 @@ type Int64Value struct {
 }
 
 func (i *Int64Value) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
-	if err != nil {
-		err = numError(err)
-	}
+	v, err := ParseInt64(s, 0)
 	i.value = v
 	i.set = true
 
@@ -23,3 +20,13 @@ func (i *Int64Value) Get() interface{} { return i.value }
 func (i *Int64Value) String() string { return strconv.Itoa(int(i.value)) }
 
 func (i *Int64Value) IsSet() bool { return i.set }
+
+// ParseInt64 parses an int64 value in the base. The base 0 accepts a base prefix, e.g. 0x10, the same way as the int64 flag.
+func ParseInt64(s string, base int) (int64, error) {
+	v, err := strconv.ParseInt(s, base, 64)
+	if err != nil {
+		return v, NumError(err)
+	}
+
+	return v, nil
+}
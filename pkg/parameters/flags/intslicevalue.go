@@ -0,0 +1,52 @@
+package flags
+
+import (
+	"strconv"
+	"strings"
+)
+
+type IntSliceValue struct {
+	set   bool
+	value []int
+}
+
+// Set appends comma-separated values to the slice. The first call replaces the default value.
+func (i *IntSliceValue) Set(s string) error {
+	values, err := ParseIntSlice(s, 0)
+	if err != nil {
+		return err
+	}
+	if !i.set {
+		i.value = nil
+	}
+	i.value = append(i.value, values...)
+	i.set = true
+
+	return nil
+}
+
+func (i *IntSliceValue) Get() interface{} { return i.value }
+
+func (i *IntSliceValue) String() string {
+	values := make([]string, len(i.value))
+	for index, v := range i.value {
+		values[index] = strconv.Itoa(v)
+	}
+	return strings.Join(values, ",")
+}
+
+func (i *IntSliceValue) IsSet() bool { return i.set }
+
+// ParseIntSlice parses comma-separated int values, each value is parsed by ParseInt in the base after spaces are trimmed.
+func ParseIntSlice(s string, base int) ([]int, error) {
+	values := make([]int, 0, 1)
+	for _, str := range ParseStringSlice(s) {
+		v, err := ParseInt(str, base)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
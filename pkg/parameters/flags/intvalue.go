@@ -8,11 +8,8 @@ type IntValue struct {
 }
 
 func (i *IntValue) Set(s string) error {
-	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
-	if err != nil {
-		err = numError(err)
-	}
-	i.value = int(v)
+	v, err := ParseInt(s, 0)
+	i.value = v
 	i.set = true
 
 	return err
@@ -23,3 +20,13 @@ func (i *IntValue) Get() interface{} { return i.value }
 func (i *IntValue) String() string { return strconv.Itoa(i.value) }
 
 func (i *IntValue) IsSet() bool { return i.set }
+
+// ParseInt parses an int value in the base. The base 0 accepts a base prefix, e.g. 0x10, the same way as the int flag.
+func ParseInt(s string, base int) (int, error) {
+	v, err := strconv.ParseInt(s, base, strconv.IntSize)
+	if err != nil {
+		return int(v), NumError(err)
+	}
+
+	return int(v), nil
+}
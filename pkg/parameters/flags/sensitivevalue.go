@@ -0,0 +1,24 @@
+package flags
+
+import "flag"
+
+// Redacted replaces values of sensitive flags in String.
+const Redacted = "******"
+
+// SensitiveValue wraps a flag value and redacts it in String, so the value isn't printed by defaults and logs.
+type SensitiveValue struct {
+	flag.Value
+}
+
+func (s *SensitiveValue) String() string {
+	if s.Value == nil || s.Value.String() == "" {
+		return ""
+	}
+
+	return Redacted
+}
+
+func (s *SensitiveValue) IsBoolFlag() bool {
+	b, ok := s.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
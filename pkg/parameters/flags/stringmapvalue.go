@@ -0,0 +1,56 @@
+package flags
+
+import (
+	"sort"
+	"strings"
+)
+
+type StringMapValue struct {
+	set   bool
+	value map[string]string
+}
+
+// Set adds comma-separated key=value pairs to the map. The first call replaces the default value.
+func (m *StringMapValue) Set(s string) error {
+	values, err := ParseStringMap(s)
+	if err != nil {
+		return err
+	}
+	if !m.set {
+		m.value = map[string]string{}
+	}
+	for key, value := range values {
+		m.value[key] = value
+	}
+	m.set = true
+
+	return nil
+}
+
+func (m *StringMapValue) Get() interface{} { return m.value }
+
+func (m *StringMapValue) String() string {
+	pairs := make([]string, 0, len(m.value))
+	for key, value := range m.value {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m *StringMapValue) IsSet() bool { return m.set }
+
+// ParseStringMap parses comma-separated key=value pairs, e.g. a=1,b=2.
+func ParseStringMap(s string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || key == "" {
+			return nil, ErrParse
+		}
+		values[key] = strings.TrimSpace(kv[1])
+	}
+
+	return values, nil
+}
@@ -0,0 +1,35 @@
+package flags
+
+import "strings"
+
+type StringSliceValue struct {
+	set   bool
+	value []string
+}
+
+// Set appends comma-separated values to the slice. The first call replaces the default value.
+func (s *StringSliceValue) Set(val string) error {
+	if !s.set {
+		s.value = nil
+	}
+	s.value = append(s.value, ParseStringSlice(val)...)
+	s.set = true
+
+	return nil
+}
+
+func (s *StringSliceValue) Get() interface{} { return s.value }
+
+func (s *StringSliceValue) String() string { return strings.Join(s.value, ",") }
+
+func (s *StringSliceValue) IsSet() bool { return s.set }
+
+// ParseStringSlice parses comma-separated values, spaces around values are trimmed.
+func ParseStringSlice(s string) []string {
+	values := strings.Split(s, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+	}
+
+	return values
+}
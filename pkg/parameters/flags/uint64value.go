@@ -8,10 +8,7 @@ type Uint64Value struct {
 }
 
 func (i *Uint64Value) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
-	if err != nil {
-		err = numError(err)
-	}
+	v, err := ParseUint64(s, 0)
 	i.value = v
 	i.set = true
 	return err
@@ -22,3 +19,13 @@ func (i *Uint64Value) Get() interface{} { return i.value }
 func (i *Uint64Value) String() string { return strconv.FormatUint(i.value, 10) }
 
 func (i *Uint64Value) IsSet() bool { return i.set }
+
+// ParseUint64 parses a uint64 value in the base. The base 0 accepts a base prefix, e.g. 0x10, the same way as the uint64 flag.
+func ParseUint64(s string, base int) (uint64, error) {
+	v, err := strconv.ParseUint(s, base, 64)
+	if err != nil {
+		return v, NumError(err)
+	}
+
+	return v, nil
+}
@@ -8,11 +8,8 @@ type UintValue struct {
 }
 
 func (i *UintValue) Set(s string) error {
-	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
-	if err != nil {
-		err = numError(err)
-	}
-	i.value = uint(v)
+	v, err := ParseUint(s, 0)
+	i.value = v
 	i.set = true
 	return err
 }
@@ -22,3 +19,13 @@ func (i *UintValue) Get() interface{} { return i.value }
 func (i *UintValue) String() string { return strconv.FormatUint(uint64(i.value), 10) }
 
 func (i *UintValue) IsSet() bool { return i.set }
+
+// ParseUint parses a uint value in the base. The base 0 accepts a base prefix, e.g. 0x10, the same way as the uint flag.
+func ParseUint(s string, base int) (uint, error) {
+	v, err := strconv.ParseUint(s, base, strconv.IntSize)
+	if err != nil {
+		return uint(v), NumError(err)
+	}
+
+	return uint(v), nil
+}
@@ -1,149 +1,435 @@
 package parameters
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/barchart/common-go/pkg/configuration/aws/parameterstore"
 	"github.com/barchart/common-go/pkg/configuration/database"
 	"github.com/barchart/common-go/pkg/parameters/flags"
 )
 
-// convertStrings converts a string to a typeValue and returns it
-func convertString(str string, typeValue string) interface{} {
+// convertString converts a string to a typeValue and returns it.
+// Returns flags.ErrParse or flags.ErrRange if the string can't be converted.
+// Integers are parsed in the base 10, so a leading zero doesn't mean an octal value, base prefixes are accepted only by flags.
+func convertString(str string, typeValue string) (interface{}, error) {
 	switch typeValue {
 	case boolType:
 		{
-			value, _ := strconv.ParseBool(str)
-			return value
+			value, err := strconv.ParseBool(str)
+			return value, flags.NumError(err)
+		}
+	case durationType:
+		{
+			value, err := time.ParseDuration(str)
+			if err != nil {
+				return value, flags.ErrParse
+			}
+			return value, nil
 		}
 	case float64Type:
 		{
-			value, _ := strconv.ParseFloat(str, 64)
-			return value
+			value, err := strconv.ParseFloat(str, 64)
+			return value, flags.NumError(err)
+		}
+	case float64SliceType:
+		{
+			value := make([]float64, 0, 1)
+			err := convertList(str, &value, func(list string) (err error) {
+				value, err = flags.ParseFloat64Slice(list)
+				return err
+			})
+			return value, err
 		}
 	case intType:
 		{
-			value, _ := strconv.ParseInt(str, 10, 32)
-			return int(value)
+			return flags.ParseInt(str, 10)
+		}
+	case intSliceType:
+		{
+			value := make([]int, 0, 1)
+			err := convertList(str, &value, func(list string) (err error) {
+				value, err = flags.ParseIntSlice(list, 10)
+				return err
+			})
+			return value, err
 		}
 	case int64Type:
 		{
-			value, _ := strconv.ParseInt(str, 10, 64)
-			return value
+			return flags.ParseInt64(str, 10)
 		}
 	case stringType:
 		{
-			return str
+			return str, nil
+		}
+	case stringMapType:
+		{
+			return convertMap(str)
+		}
+	case stringSliceType:
+		{
+			value := make([]string, 0, 1)
+			err := convertList(str, &value, func(list string) error {
+				value = flags.ParseStringSlice(list)
+				return nil
+			})
+			return value, err
 		}
 	case uintType:
 		{
-			value, _ := strconv.ParseUint(str, 10, 32)
-			return uint(value)
+			return flags.ParseUint(str, 10)
 		}
 	case uint64Type:
 		{
-			value, _ := strconv.ParseUint(str, 10, 64)
-			return value
+			return flags.ParseUint64(str, 10)
 		}
 	case databaseType:
 		{
 			value := database.Database{}
-			_ = json.Unmarshal([]byte(str), &value)
-			return value
+			if err := json.Unmarshal([]byte(str), &value); err != nil {
+				return value, flags.ErrParse
+			}
+			return value, nil
+		}
+	}
+
+	if typ, ok := getCustomType(typeValue); ok {
+		return typ.parse(str)
+	}
+
+	return str, nil
+}
+
+// convertList converts a JSON array or a comma-separated list to the slice.
+// A JSON array is unmarshalled to the target, otherwise the list is passed to the parse function,
+// which parses it the same way as the flag of the list.
+func convertList(str string, target interface{}, parse func(list string) error) error {
+	if strings.HasPrefix(strings.TrimSpace(str), "[") {
+		if err := json.Unmarshal([]byte(str), target); err != nil {
+			return flags.ErrParse
 		}
+		return nil
 	}
 
-	return str
+	return parse(str)
+}
+
+// convertMap converts a JSON object or comma-separated key=value pairs to the map.
+// Values of a JSON object which aren't strings are kept as JSON.
+func convertMap(str string) (map[string]string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(str), "{") {
+		return flags.ParseStringMap(str)
+	}
+
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(str), &object); err != nil {
+		return nil, flags.ErrParse
+	}
+
+	value := make(map[string]string, len(object))
+	for key, item := range object {
+		if itemString, ok := item.(string); ok {
+			value[key] = itemString
+		} else {
+			itemJSON, _ := json.Marshal(item)
+			value[key] = string(itemJSON)
+		}
+	}
+
+	return value, nil
+}
+
+// convertParameterValue converts a raw value of the parameter from the source.
+// Returns *InvalidValueError if the value can't be converted, the raw value of a secret or a sensitive parameter is redacted.
+func convertParameterValue(param Parameter, raw string, source string) (interface{}, error) {
+	value, err := convertString(raw, param.valueType)
+	if err != nil {
+		// values of remote stores may be SecureString parameters or secrets, so they are never shown
+		if source == SourceSecretsManager || source == SourceParameterStore || param.IsSensitive() {
+			raw = redactedValue
+		}
+
+		return nil, &InvalidValueError{Name: param.Name, Source: source, Raw: raw, Err: err}
+	}
+
+	return value, nil
 }
 
 // getAWSSecretsRegion gets AwsRegionSecrets value from a AWS-REGION-SECRETS flag or env variable and returns it
-func getAWSSecretsRegion() string {
-	flg := flag.Lookup(AwsRegionSecrets)
-	region := flg.Value.String()
-	if !flg.Value.(*flags.StringValue).IsSet() {
-		region = os.Getenv(AwsRegionSecrets)
-		if region == "" {
-			region = flg.DefValue
+func (s *ParameterSet) getAWSSecretsRegion() string {
+	flg := s.flags.Lookup(AwsRegionSecrets)
+	if flg != nil && flg.Value.(*flags.StringValue).IsSet() {
+		return flg.Value.String()
+	}
+
+	if region := os.Getenv(AwsRegionSecrets); region != "" {
+		return region
+	}
+
+	return defaultAWSRegion
+}
+
+// loadDotEnvFiles loads dotenv files with or without the {stage} placeholder
+func (s *ParameterSet) loadDotEnvFiles(staged bool) error {
+	if s.dotenv == nil {
+		s.dotenv = map[string]dotenvValue{}
+	}
+
+	lookup := func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
 		}
+		value, ok := s.dotenv[name]
+		return value.value, ok
 	}
 
-	return region
+	for _, file := range s.dotenvFiles {
+		if strings.Contains(file, stagePlaceholder) != staged {
+			continue
+		}
+
+		if staged {
+			stage, ok := s.result[StageParameter].(string)
+			if !ok || stage == "" {
+				continue
+			}
+			file = strings.ReplaceAll(file, stagePlaceholder, strings.ToLower(stage))
+		}
+
+		err := loadDotEnvFile(file, lookup, func(key, value string) {
+			s.dotenv[key] = dotenvValue{value: value, file: file}
+		})
+		if err != nil {
+			return fmt.Errorf("unable to load the dotenv file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lookupEnv returns a value of the environment variable and its source.
+// Real environment variables override variables loaded from dotenv files.
+func (s *ParameterSet) lookupEnv(name string) (string, string, bool) {
+	if value := os.Getenv(name); value != "" {
+		return value, SourceEnv, true
+	}
+
+	if value, ok := s.dotenv[name]; ok && value.value != "" {
+		return value.value, SourceDotEnv, true
+	}
+
+	return "", "", false
 }
 
-// getValueFromAWSSecretsManager returns a Parameter value from the AWS Secrets Manager
-func getValueFromAWSSecretsManager(param Parameter) interface{} {
-	if param.Options.SecretsManagerEnable {
-		if sm != nil && smError == nil {
-			name := param.Name
-
-			if param.Options.StageSensitive {
-				if stage, ok := defaultParams.result[StageParameter]; ok {
-					nameWithStage := fmt.Sprintf("%v_%v", name, strings.ToUpper(stage.(string)))
-					value, _, err := sm.GetValue(nameWithStage)
-					if err == nil {
-						return convertString(value, param.valueType)
-					}
-				}
-			} else {
-				if stage, ok := defaultParams.result[StageParameter]; ok {
-					nameWithStage := fmt.Sprintf("%v_%v", name, strings.ToUpper(stage.(string)))
-					value, _, err := sm.GetValue(nameWithStage)
-					if err == nil {
-						return convertString(value, param.valueType)
-					}
-				}
-				value, _, err := sm.GetValue(name)
-				if err == nil {
-					return convertString(value, param.valueType)
-				}
+// loadConfigFile loads values from the config file. A path from the CONFIG flag or env variable overrides a path set by SetConfigFile.
+// If the CONFIG parameter is defined in the collection or the CONFIG flag was defined by the application,
+// only the path set by SetConfigFile is used.
+func (s *ParameterSet) loadConfigFile() error {
+	path := s.configFile
+
+	if _, ok := s.collection[ConfigParameter]; !ok {
+		if value, ok := s.flags.Lookup(ConfigParameter).Value.(*flags.StringValue); ok {
+			if value.IsSet() {
+				path = value.String()
+			} else if env, _, ok := s.lookupEnv(ConfigParameter); ok {
+				path = env
 			}
 		}
 	}
 
+	if path == "" {
+		return nil
+	}
+
+	values, err := loadConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to load the config file: %w", err)
+	}
+
+	s.fileValues = values
+
 	return nil
 }
 
+// lookupSecret returns a raw value of the Parameter from the AWS Secrets Manager.
+// If the parameter has a secret key, the value of the field of the key/value secret is returned.
+// Errors of requests are ignored, but an error is returned if the context is done.
+func (s *ParameterSet) lookupSecret(ctx context.Context, param Parameter) (string, bool, error) {
+	if !s.secretsEnabled(param) {
+		return "", false, nil
+	}
+
+	if _, err := s.secretsManager(); err != nil {
+		return "", false, err
+	}
+
+	names := s.secretNames(param)
+	// names of aliases follow names of the parameter
+	canonical := len(names) / len(secretBases(param))
+
+	for i, name := range names {
+		value, err := s.getSecret(ctx, name)
+		if err != nil && ctx.Err() != nil {
+			return "", false, ctx.Err()
+		}
+
+		found := err == nil
+		if found && param.secretKey != "" {
+			name = name + "#" + param.secretKey
+			value, found = secretField(value, param.secretKey)
+		}
+
+		s.trace(param, SourceSecretsManager, name, found)
+		if found {
+			if i >= canonical {
+				s.warnAlias(param, SourceSecretsManager, name)
+			}
+			return value, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// lookupParameterStore returns a raw value of the Parameter from AWS SSM Parameter Store
+func (s *ParameterSet) lookupParameterStore(ctx context.Context, param Parameter) (string, bool, error) {
+	if !param.Options.ParameterStoreEnable || s.offline {
+		return "", false, nil
+	}
+
+	ps, err := s.parameterStore()
+	if err != nil {
+		return "", false, err
+	}
+
+	template := s.psPath
+	if template == "" {
+		template = secretNamePlaceholder
+	}
+
+	if strings.Contains(template, stagePlaceholder) {
+		stage, ok := s.result[StageParameter].(string)
+		if !ok || stage == "" {
+			return "", false, nil
+		}
+		template = strings.ReplaceAll(template, stagePlaceholder, strings.ToLower(stage))
+	}
+
+	name := strings.ReplaceAll(template, secretNamePlaceholder, param.Name)
+
+	if path := strings.TrimSuffix(template, "/"+secretNamePlaceholder); path != template && strings.HasPrefix(path, "/") {
+		values, ok := s.psCache[path]
+		if !ok {
+			var err error
+			values, err = ps.GetValuesByPathWithContext(ctx, path)
+			if err != nil {
+				return "", false, err
+			}
+			if s.psCache == nil {
+				s.psCache = map[string]map[string]string{}
+			}
+			s.psCache[path] = values
+		}
+
+		value, found := values[name]
+		s.trace(param, SourceParameterStore, name, found)
+
+		return value, found, nil
+	}
+
+	value, err := ps.GetValueWithContext(ctx, name)
+	if err != nil {
+		if errors.Is(err, parameterstore.ErrNotFound) {
+			s.trace(param, SourceParameterStore, name, false)
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	s.trace(param, SourceParameterStore, name, true)
+
+	return value, true, nil
+}
+
+// flagValue returns a value of the flag unwrapped from flags.SensitiveValue
+func flagValue(flg *flag.Flag) flag.Value {
+	if sensitive, ok := flg.Value.(*flags.SensitiveValue); ok {
+		return sensitive.Value
+	}
+
+	return flg.Value
+}
+
 // getValueFromFlag returns the value of the desired type from the flag
 func getValueFromFlag(flg *flag.Flag, typeValue string) (interface{}, bool) {
+	value := flagValue(flg)
+
 	switch typeValue {
 	case boolType:
 		{
-			return flg.Value.(*flags.BoolValue).Get(), flg.Value.(*flags.BoolValue).IsSet()
+			return value.(*flags.BoolValue).Get(), value.(*flags.BoolValue).IsSet()
+		}
+	case durationType:
+		{
+			return value.(*flags.DurationValue).Get(), value.(*flags.DurationValue).IsSet()
 		}
 	case float64Type:
 		{
-			return flg.Value.(*flags.Float64Value).Get(), flg.Value.(*flags.Float64Value).IsSet()
+			return value.(*flags.Float64Value).Get(), value.(*flags.Float64Value).IsSet()
+		}
+	case float64SliceType:
+		{
+			return value.(*flags.Float64SliceValue).Get(), value.(*flags.Float64SliceValue).IsSet()
 		}
 	case intType:
 		{
-			return flg.Value.(*flags.IntValue).Get(), flg.Value.(*flags.IntValue).IsSet()
+			return value.(*flags.IntValue).Get(), value.(*flags.IntValue).IsSet()
 		}
 	case int64Type:
 		{
-			return flg.Value.(*flags.Int64Value).Get(), flg.Value.(*flags.Int64Value).IsSet()
+			return value.(*flags.Int64Value).Get(), value.(*flags.Int64Value).IsSet()
 		}
 	case stringType:
 		{
-			return flg.Value.(*flags.StringValue).Get(), flg.Value.(*flags.StringValue).IsSet()
+			return value.(*flags.StringValue).Get(), value.(*flags.StringValue).IsSet()
+		}
+	case intSliceType:
+		{
+			return value.(*flags.IntSliceValue).Get(), value.(*flags.IntSliceValue).IsSet()
+		}
+	case stringMapType:
+		{
+			return value.(*flags.StringMapValue).Get(), value.(*flags.StringMapValue).IsSet()
+		}
+	case stringSliceType:
+		{
+			return value.(*flags.StringSliceValue).Get(), value.(*flags.StringSliceValue).IsSet()
 		}
 	case uintType:
 		{
-			return flg.Value.(*flags.UintValue).Get(), flg.Value.(*flags.UintValue).IsSet()
+			return value.(*flags.UintValue).Get(), value.(*flags.UintValue).IsSet()
 		}
 	case uint64Type:
 		{
-			return flg.Value.(*flags.Uint64Value).Get(), flg.Value.(*flags.Uint64Value).IsSet()
+			return value.(*flags.Uint64Value).Get(), value.(*flags.Uint64Value).IsSet()
 		}
 	case databaseType:
 		{
-			return flg.Value.(*flags.DatabaseValue).Get(), flg.Value.(*flags.DatabaseValue).IsSet()
+			return value.(*flags.DatabaseValue).Get(), value.(*flags.DatabaseValue).IsSet()
 		}
 	}
 
+	if _, ok := getCustomType(typeValue); ok {
+		return value.(*flags.CustomValue).Get(), value.(*flags.CustomValue).IsSet()
+	}
+
 	return nil, false
 }
 
@@ -0,0 +1,44 @@
+package parameters
+
+import "strings"
+
+// secretNamePlaceholder is replaced by a parameter name in Naming.SecretTemplate
+const secretNamePlaceholder = "{name}"
+
+// Naming is a naming strategy of flags, environment variables and secrets of parameters.
+// The zero value uses a parameter name for all of them.
+// EnvPrefix - a prefix of environment variables, e.g. APP_ (an env variable of the DB_HOST parameter is APP_DB_HOST)
+// KebabFlags - flags are named in the kebab-case, e.g. --db-host for the DB_HOST parameter
+// SecretTemplate - a template of secret names, where {name} is replaced by the parameter name, e.g. my-app/{name}
+type Naming struct {
+	EnvPrefix      string
+	KebabFlags     bool
+	SecretTemplate string
+}
+
+// flagName returns a flag name of the parameter
+func (n Naming) flagName(name string) string {
+	if n.KebabFlags {
+		return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+	}
+
+	return name
+}
+
+// envName returns an environment variable name of the parameter in the SCREAMING_SNAKE_CASE
+func (n Naming) envName(name string) string {
+	if n.EnvPrefix == "" && !n.KebabFlags {
+		return name
+	}
+
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(n.EnvPrefix + name))
+}
+
+// secretName returns a secret name of the parameter
+func (n Naming) secretName(name string) string {
+	if n.SecretTemplate == "" {
+		return name
+	}
+
+	return strings.ReplaceAll(n.SecretTemplate, secretNamePlaceholder, name)
+}
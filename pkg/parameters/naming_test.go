@@ -0,0 +1,39 @@
+package parameters
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaming(t *testing.T) {
+	naming := Naming{EnvPrefix: "APP_", KebabFlags: true, SecretTemplate: "my-app/{name}"}
+
+	assert.Equal(t, "db-host", naming.flagName("DB_HOST"), "should return a flag name in the kebab-case")
+	assert.Equal(t, "APP_DB_HOST", naming.envName("DB_HOST"), "should return an env name with the prefix")
+	assert.Equal(t, "APP_DB_HOST", naming.envName("db-host"), "should return an env name in the SCREAMING_SNAKE_CASE")
+	assert.Equal(t, "my-app/DB_HOST", naming.secretName("DB_HOST"), "should return a secret name by the template")
+
+	assert.Equal(t, "DB_HOST", Naming{}.flagName("DB_HOST"), "should return a parameter name")
+	assert.Equal(t, "DB_HOST", Naming{}.envName("DB_HOST"), "should return a parameter name")
+	assert.Equal(t, "DB_HOST", Naming{}.secretName("DB_HOST"), "should return a parameter name")
+}
+
+func TestParameterSet_SetNaming(t *testing.T) {
+	_ = os.Setenv("NAMING_DB_PORT", "1234")
+	defer os.Unsetenv("NAMING_DB_PORT")
+
+	set := NewSet("test")
+	set.SetNaming(Naming{EnvPrefix: "NAMING_", KebabFlags: true})
+	set.AddString("DB_HOST", "", "A host of database", false)
+	set.AddInt("DB_PORT", 0, "A port of database", false)
+
+	setResult, err := set.ParseE([]string{"--db-host=example.com"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "example.com", setResult.GetString("DB_HOST"), "should return a value from a kebab-case flag")
+	assert.Equal(t, 1234, setResult.GetInt("DB_PORT"), "should return a value from a prefixed env variable")
+	assert.Equal(t, "db-host", set.GetCollection()["DB_HOST"].FlagName(), "should return a flag name")
+	assert.Equal(t, "NAMING_DB_PORT", set.GetCollection()["DB_PORT"].EnvName(), "should return an env name")
+}
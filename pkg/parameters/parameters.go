@@ -1,12 +1,14 @@
 package parameters
 
 import (
-	"flag"
+	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
+	"time"
 
-	"github.com/barchart/common-go/pkg/configuration"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
 	"github.com/barchart/common-go/pkg/configuration/database"
 	"github.com/barchart/common-go/pkg/logger"
@@ -21,237 +23,342 @@ type Parameter struct {
 	Required     bool
 	Options      Options
 	valueType    string
+	flagName     string
+	envName      string
+	secretName   string
+	secretKey    string
+	set          *ParameterSet
+}
+
+// FlagName returns a name of the flag of the parameter.
+func (p Parameter) FlagName() string {
+	return p.flagName
+}
+
+// EnvName returns a name of the environment variable of the parameter.
+func (p Parameter) EnvName() string {
+	return p.envName
+}
+
+// SecretName returns a name of the secret of the parameter in the AWS Secrets Manager.
+func (p Parameter) SecretName() string {
+	return p.secretName
+}
+
+// SecretKey returns a key of the field of the key/value secret which holds the parameter value.
+// Returns an empty string if the whole secret is the value.
+func (p Parameter) SecretKey() string {
+	return p.secretKey
 }
 
 // Options is a struct defines an options for parameters package
 // SecretsManagerEnable - search a parameter in AWS Secrets Manager
-// StageSensitive - the parameter a stage sensitive e.g: NAME_STAGE, where STAGE is a value of STAGE parameter
+// StageSensitive - the parameter a stage sensitive e.g: NAME_STAGE, where STAGE is a value of STAGE parameter,
+// the secret is searched only by NAME_STAGE, the env variable and flags of SetStageFlags are searched by NAME_STAGE before NAME
+// ParameterStoreEnable - search a parameter in AWS SSM Parameter Store
+// Sensitive - redact the parameter value by Redact, in usage, flags and errors,
+// parameters with the SecretsManagerEnable option are sensitive by default
+// NotSensitive - don't redact the value of the parameter with the SecretsManagerEnable option, e.g. a host of database
+// Validate - validation rules of the pkg/validation validator, e.g. "min=1,max=65535" or "oneof=dev stage prod"
+// SecretKey - a key of the field of the key/value secret, e.g. "host" or "NAME#host" to use a field of the NAME secret
+// Aliases - old names of the parameter which are accepted by flags, env variables, the config file and AWS Secrets Manager
+// Deprecated - a deprecation message which is logged if the parameter is provided
+// StageDefaults - default values by values of the STAGE parameter, e.g. {"DEV": "localhost", "PROD": "db.example.com"}
 type Options struct {
 	SecretsManagerEnable bool
 	StageSensitive       bool
+	ParameterStoreEnable bool
+	Sensitive            bool
+	NotSensitive         bool
+	Validate             string
+	SecretKey            string
+	Aliases              []string
+	Deprecated           string
+	StageDefaults        map[string]interface{}
+}
+
+// IsSensitive returns true if the parameter value must be redacted.
+// Parameters with the SecretsManagerEnable option are sensitive unless the NotSensitive option is set.
+func (p Parameter) IsSensitive() bool {
+	return p.Options.Sensitive || (p.Options.SecretsManagerEnable && !p.Options.NotSensitive)
+}
+
+// IsList returns true if the parameter holds a list of values.
+func (p Parameter) IsList() bool {
+	switch p.valueType {
+	case float64SliceType, intSliceType, stringSliceType:
+		return true
+	}
+
+	return false
 }
 
-// parameters is a struct that holds the collection of Parameter
-type parameters struct {
-	collection map[string]Parameter
-	result     Results
-	parsed     bool
+// IsMap returns true if the parameter holds key/value pairs.
+func (p Parameter) IsMap() bool {
+	return p.valueType == stringMapType
+}
+
+// FormatSafe returns a string representation of the parameter value like Format, but a value of a sensitive parameter is redacted.
+func (p Parameter) FormatSafe(value interface{}) string {
+	if p.IsSensitive() {
+		return redactedValue
+	}
+
+	return p.Format(value)
+}
+
+// Format returns a string representation of the parameter value. A list is rendered as comma-separated values,
+// key/value pairs are rendered as comma-separated key=value pairs and custom types are rendered by their format function.
+// A database is rendered as JSON with the redacted password.
+func (p Parameter) Format(value interface{}) string {
+	if typ, ok := getCustomType(p.valueType); ok {
+		return typ.format(value)
+	}
+
+	if db, ok := value.(database.Database); ok {
+		return flags.FormatDatabase(db)
+	}
+
+	if p.IsMap() {
+		v := reflect.ValueOf(value)
+		pairs := make([]string, 0, v.Len())
+
+		for _, key := range v.MapKeys() {
+			pairs = append(pairs, fmt.Sprintf("%v=%v", key.Interface(), v.MapIndex(key).Interface()))
+		}
+
+		sort.Strings(pairs)
+
+		return strings.Join(pairs, ",")
+	}
+
+	if p.IsList() {
+		v := reflect.ValueOf(value)
+		items := make([]string, 0, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, fmt.Sprint(v.Index(i).Interface()))
+		}
+
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(value)
 }
 
 var (
-	sm            *secretsmanager.SecretsManager
-	smError       error
 	log           = logger.Log
-	defaultParams *parameters
+	defaultParams *ParameterSet
 )
 
 func init() {
-	defaultParams = &parameters{collection: map[string]Parameter{}, result: map[string]interface{}{}}
+	defaultParams = newSet(os.Args[0], flags.CommandLine)
+}
+
+// SetNaming sets a naming strategy of flags, environment variables and secrets.
+// It must be called before parameters are added.
+func SetNaming(naming Naming) {
+	defaultParams.SetNaming(naming)
+}
+
+// SetDotEnvFiles sets paths of dotenv files which are loaded by Parse, e.g. ".env", ".env.{stage}".
+// See ParameterSet.SetDotEnvFiles for details.
+func SetDotEnvFiles(files ...string) {
+	defaultParams.SetDotEnvFiles(files...)
+}
+
+// LookupDotEnv returns a value of the environment variable loaded from a dotenv file and a path of the file.
+func LookupDotEnv(name string) (value string, file string, ok bool) {
+	return defaultParams.LookupDotEnv(name)
+}
+
+// SetParameterStorePath sets a template of names of parameters in AWS SSM Parameter Store, e.g. /app/{stage}/{name}.
+// See ParameterSet.SetParameterStorePath for details.
+func SetParameterStorePath(template string) {
+	defaultParams.SetParameterStorePath(template)
+}
+
+// SetAWSConfig sets additional configs of AWS clients, e.g. to set an endpoint or credentials.
+func SetAWSConfig(configs ...*aws.Config) {
+	defaultParams.SetAWSConfig(configs...)
+}
+
+// SetLookupTimeout sets a limit of the total time spent on lookups of values during Parse.
+// See ParameterSet.SetLookupTimeout for details.
+func SetLookupTimeout(timeout time.Duration) {
+	defaultParams.SetLookupTimeout(timeout)
+}
+
+// SetOffline enables the offline mode. AWS Secrets Manager and AWS SSM Parameter Store are skipped in the offline mode.
+func SetOffline(offline bool) {
+	defaultParams.SetOffline(offline)
+}
+
+// SetSecretsRetryPolicy sets a policy of retries of throttled requests and internal service errors of the AWS Secrets Manager.
+func SetSecretsRetryPolicy(policy secretsmanager.RetryPolicy) {
+	defaultParams.SetSecretsRetryPolicy(policy)
+}
+
+// SetStageFlags sets stages of flags of stage sensitive parameters, e.g. "DEV", "PROD".
+// See ParameterSet.SetStageFlags for details.
+func SetStageFlags(stages ...string) {
+	defaultParams.SetStageFlags(stages...)
+}
+
+// SetSources sets the ordered list of sources of parameter values.
+// The default order is FlagSource, EnvSource, FileSource, SecretsManagerSource, ParameterStoreSource.
+func SetSources(sources ...Source) {
+	defaultParams.SetSources(sources...)
 }
 
 // Add is alias for AddString
 func Add(name string, value string, usage string, required bool, options ...Options) {
-	AddString(name, value, usage, required, options...)
+	defaultParams.Add(name, value, usage, required, options...)
 }
 
 // AddBool defines a bool Parameter with specified name, default value, and usage string.
 func AddBool(name string, value bool, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    boolType,
-	}
+	defaultParams.AddBool(name, value, usage, required, options...)
+}
 
-	flags.Bool(name, value, usage)
+// AddCustom defines a Parameter of the custom type with specified name, default value, and usage string.
+// The type must be registered by RegisterType.
+func AddCustom(name string, value interface{}, usage string, required bool, typ string, options ...Options) {
+	defaultParams.AddCustom(name, value, usage, required, typ, options...)
 }
 
 // AddDatabase defines a database Parameter with specified name, default value, and usage string.
 func AddDatabase(name string, value database.Database, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    databaseType,
-	}
+	defaultParams.AddDatabase(name, value, usage, required, options...)
+}
 
-	flags.Database(name, value, usage)
+// AddDuration defines a time.Duration Parameter with specified name, default value, and usage string.
+func AddDuration(name string, value time.Duration, usage string, required bool, options ...Options) {
+	defaultParams.AddDuration(name, value, usage, required, options...)
 }
 
 // AddFloat64 defines a float64 Parameter with specified name, default value, and usage string.
 func AddFloat64(name string, value float64, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    float64Type,
-	}
+	defaultParams.AddFloat64(name, value, usage, required, options...)
+}
 
-	flags.Float64(name, value, usage)
+// AddFloat64Slice defines a []float64 Parameter with specified name, default value, and usage string.
+// The value is provided by repeated flags, a comma-separated environment variable or a JSON array secret.
+func AddFloat64Slice(name string, value []float64, usage string, required bool, options ...Options) {
+	defaultParams.AddFloat64Slice(name, value, usage, required, options...)
 }
 
 // AddInt defines a int Parameter with specified name, default value, and usage string.
 func AddInt(name string, value int, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    intType,
-	}
-
-	flags.Int(name, value, usage)
+	defaultParams.AddInt(name, value, usage, required, options...)
 }
 
 // AddInt64 defines a int64 Parameter with specified name, default value, and usage string.
 func AddInt64(name string, value int64, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    int64Type,
-	}
+	defaultParams.AddInt64(name, value, usage, required, options...)
+}
 
-	flags.Int64(name, value, usage)
+// AddIntSlice defines a []int Parameter with specified name, default value, and usage string.
+// The value is provided by repeated flags, a comma-separated environment variable or a JSON array secret.
+func AddIntSlice(name string, value []int, usage string, required bool, options ...Options) {
+	defaultParams.AddIntSlice(name, value, usage, required, options...)
 }
 
 // AddString defines a string Parameter with specified name, default value, and usage string.
 func AddString(name string, value string, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    stringType,
-	}
+	defaultParams.AddString(name, value, usage, required, options...)
+}
 
-	flags.String(name, value, usage)
+// AddStringMap defines a map[string]string Parameter with specified name, default value, and usage string.
+// The value is provided by comma-separated key=value pairs of flags and environment variables or a key/value secret.
+func AddStringMap(name string, value map[string]string, usage string, required bool, options ...Options) {
+	defaultParams.AddStringMap(name, value, usage, required, options...)
+}
+
+// AddStringSlice defines a []string Parameter with specified name, default value, and usage string.
+// The value is provided by repeated flags, a comma-separated environment variable or a JSON array secret.
+func AddStringSlice(name string, value []string, usage string, required bool, options ...Options) {
+	defaultParams.AddStringSlice(name, value, usage, required, options...)
 }
 
 // AddUint defines a uint Parameter with specified name, default value, and usage string.
 func AddUint(name string, value uint, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    uintType,
-	}
-
-	flags.Uint(name, value, usage)
+	defaultParams.AddUint(name, value, usage, required, options...)
 }
 
 // AddUint64 defines a uint64 Parameter with specified name, default value, and usage string.
 func AddUint64(name string, value uint64, usage string, required bool, options ...Options) {
-	defaultParams.collection[name] = Parameter{
-		Name:         name,
-		DefaultValue: value,
-		Usage:        usage,
-		Required:     required,
-		Options:      parseOptions(options),
-		valueType:    uint64Type,
-	}
-	flags.Uint64(name, value, usage)
+	defaultParams.AddUint64(name, value, usage, required, options...)
 }
 
-// Parse returns map of values of all defined parameters.
-func Parse() Results {
-	missing := make([]string, 0, 1)
-
-	if !defaultParams.parsed {
-		if flag.Parsed() {
-			log.Panic("flags have already parsed")
-		} else {
-			flags.String(AwsRegionSecrets, "us-east-1", "The AWS Secrets Manager region")
-		}
-
-		flag.Parse()
-
-		configuration.SetSecretsManager(getAWSSecretsRegion())
-		smm, smmError := configuration.GetSecretsManager()
-		sm = &smm
-		smError = smmError
-
-		if defaultParams.collection == nil {
-			log.Panic("parameters wasn't added")
-		}
-
-		keys := make([]string, 0, len(defaultParams.collection))
-		isStage := false
-
-		for key := range defaultParams.collection {
-			if key != StageParameter {
-				keys = append(keys, key)
-			} else {
-				isStage = true
-			}
-		}
+// RequireOneOf adds a constraint which requires a value of at least one of the parameters.
+func RequireOneOf(names ...string) {
+	defaultParams.RequireOneOf(names...)
+}
 
-		sort.Strings(keys)
+// MutuallyExclusive adds a constraint which allows a value of at most one of the parameters.
+func MutuallyExclusive(names ...string) {
+	defaultParams.MutuallyExclusive(names...)
+}
 
-		if isStage {
-			keys = append([]string{"STAGE"}, keys...)
-		}
+// RequiredIf adds a constraint which requires a value of the parameter if the predicate matches.
+func RequiredIf(name string, predicate Predicate) {
+	defaultParams.RequiredIf(name, predicate)
+}
 
-		for _, key := range keys {
-			param := defaultParams.collection[key]
-			flg := flag.Lookup(param.Name)
-			value, isSet := getValueFromFlag(flg, param.valueType)
-
-			if !isSet {
-				envValueString := os.Getenv(param.Name)
-				if envValueString != "" {
-					envValue := convertString(envValueString, param.valueType)
-					defaultParams.result[param.Name] = envValue
-				} else {
-					secretValue := getValueFromAWSSecretsManager(param)
-					if secretValue != nil {
-						defaultParams.result[param.Name] = secretValue
-					} else {
-						if param.Required {
-							missing = append(missing, param.Name)
-						} else {
-							defaultParams.result[param.Name] = value
-						}
-					}
-				}
-			} else {
-				defaultParams.result[param.Name] = value
-			}
-		}
+// GetConstraints returns constraints of parameters.
+func GetConstraints() []Constraint {
+	return defaultParams.GetConstraints()
+}
 
-		if len(missing) > 0 {
-			log.Panicf("missing required parameters: [ %v ]", strings.Join(missing, ","))
-		}
+// Bind defines parameters for fields of the struct pointed to by v and fills the fields after the parsing.
+// See ParameterSet.Bind for the supported struct tags.
+func Bind(v interface{}) error {
+	return defaultParams.Bind(v)
+}
 
-		defaultParams.parsed = true
-	}
+// Parse returns map of values of all defined parameters. The command-line arguments are parsed from os.Args[1:].
+func Parse() Results {
+	return defaultParams.Parse(os.Args[1:])
+}
 
-	return defaultParams.result
+// ParseE returns map of values of all defined parameters or an error. The command-line arguments are parsed from os.Args[1:].
+// The default set uses flag.CommandLine, so an invalid flag value exits the program with the status code 2.
+func ParseE() (Results, error) {
+	return defaultParams.ParseE(os.Args[1:])
 }
 
 // GetResults returns a result that already has been parsed.
 func GetResults() Results {
-	return defaultParams.result
+	return defaultParams.GetResults()
 }
 
 // Parsed Returns true if the parse function was used.
 func Parsed() bool {
-	return defaultParams.parsed
+	return defaultParams.Parsed()
+}
+
+// Redact returns a copy of the results where values of sensitive parameters are redacted.
+func Redact(results Results) Results {
+	return defaultParams.Redact(results)
+}
+
+// GetSource returns a provenance of the value of the parameter. See ParameterSet.Source for details.
+func GetSource(key string) (Provenance, bool) {
+	return defaultParams.Source(key)
+}
+
+// Provided returns true if a value of the parameter was provided by a source, not by a default value.
+func Provided(key string) bool {
+	return defaultParams.Provided(key)
+}
+
+// Explain returns a diagnostic report which lists every parameter, an origin of its value and lookups that missed.
+func Explain() string {
+	return defaultParams.Explain()
 }
 
 // GetCollection returns a collection of parameters.
 func GetCollection() map[string]Parameter {
-	return defaultParams.collection
+	return defaultParams.GetCollection()
 }
@@ -1,12 +1,16 @@
 package parameters
 
 import (
+	"errors"
 	"flag"
+	"math"
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/barchart/common-go/pkg/configuration/database"
+	"github.com/barchart/common-go/pkg/parameters/flags"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,6 +24,7 @@ const (
 	expectUint         = "100"
 	expectUint64       = "100"
 	expectDefaultField = "100"
+	expectDuration     = "1m30s"
 	expectDatabase     = "{\"provider\":\"mysql\",\"host\":\"https://example.com\",\"port\":54321,\"database\":\"database\",\"username\":\"user\",\"password\":\"password\"}"
 )
 
@@ -33,6 +38,7 @@ const (
 	expectedAdd          = "ADD"
 	expectedString       = "STRING"
 	expectedDefaultField = "100"
+	expectedDuration     = 90 * time.Second
 )
 
 var expectedDatabase = database.Database{
@@ -54,6 +60,7 @@ const (
 	keyUint64        = "UINT64"
 	keyAdd           = "ADD"
 	keyDatabase      = "DATABASE"
+	keyDuration      = "DURATION"
 	keyDefaultField  = "DEFAULT_FIELD"
 	keyRequiredField = "REQUIRED_FIELD"
 	keyNotExist      = "NOT_EXIST"
@@ -74,6 +81,7 @@ func setup() {
 		_ = flag.Set(keyFloat64, expectFloat64)
 		_ = flag.Set(keyUint, expectUint)
 		_ = flag.Set(keyDatabase, expectDatabase)
+		_ = flag.Set(keyDuration, expectDuration)
 		_ = os.Setenv(keyUint64, expectUint64)
 	}
 }
@@ -89,6 +97,7 @@ func TestMain(m *testing.M) {
 	AddUint(keyUint, 50, "The uint Parameter", false)
 	AddUint64(keyUint64, 50, "The uint64 Parameter", false)
 	AddDatabase(keyDatabase, database.Database{}, "The database Parameter", false)
+	AddDuration(keyDuration, time.Second, "The duration Parameter", false)
 
 	if required {
 		AddBool(keyRequiredField, false, "The required Parameter", true)
@@ -127,6 +136,7 @@ func TestResultsGetValueDirectly(t *testing.T) {
 	assert.Equal(t, expectedUint64, result[keyUint64])
 	assert.Equal(t, expectedDefaultField, result[keyDefaultField])
 	assert.Equal(t, expectedDatabase, result[keyDatabase])
+	assert.Equal(t, expectedDuration, result[keyDuration])
 }
 
 func TestResults_GetString(t *testing.T) {
@@ -161,6 +171,10 @@ func TestResults_GetDatabase(t *testing.T) {
 	assert.Equalf(t, expectedDatabase, result.GetDatabase(keyDatabase), "should return a database value from results")
 }
 
+func TestResults_GetDuration(t *testing.T) {
+	assert.Equal(t, expectedDuration, result.GetDuration(keyDuration), "should return a duration value from results")
+}
+
 func TestResults_GetStringSafe(t *testing.T) {
 	value, err := result.GetStringSafe(keyString)
 	assert.Equal(t, expectedString, value, "should return a string value from result")
@@ -209,6 +223,12 @@ func TestResults_GetDatabaseSafe(t *testing.T) {
 	assert.Nil(t, err, "an error should be nil")
 }
 
+func TestResults_GetDurationSafe(t *testing.T) {
+	value, err := result.GetDurationSafe(keyDuration)
+	assert.Equal(t, expectedDuration, value, "should return a duration value from result")
+	assert.Nil(t, err, "an error should be nil")
+}
+
 func TestResults_GetStringNotExist(t *testing.T) {
 	value, err := result.GetStringSafe(keyNotExist)
 	assert.Equal(t, "", value, "should return a default value")
@@ -256,3 +276,274 @@ func TestResults_GetDatabaseSafeNotExist(t *testing.T) {
 	assert.Equal(t, database.Database{}, value, "should return a default value")
 	assert.NotNil(t, err, "an error should not be nil")
 }
+
+func TestResults_GetDurationSafeNotExist(t *testing.T) {
+	value, err := result.GetDurationSafe(keyNotExist)
+	assert.Equal(t, time.Duration(0), value, "should return a default value")
+	assert.NotNil(t, err, "an error should not be nil")
+}
+
+func TestParameterSet_Parse(t *testing.T) {
+	set := NewSet("test")
+	set.AddString(keyString, "default", "The string Parameter", false)
+	set.AddInt(keyInt, 50, "The int Parameter", false)
+	set.AddDatabase(keyDatabase, database.Database{}, "The database Parameter", false)
+
+	setResult := set.Parse([]string{"--" + keyInt + "=" + expectInt, "--" + keyDatabase + "=" + expectDatabase})
+
+	assert.True(t, set.Parsed(), "must return true after execution of Parse() function")
+	assert.Equal(t, "default", setResult.GetString(keyString), "should return a default value")
+	assert.Equal(t, expectedInt, setResult.GetInt(keyInt), "should return an int value from the arguments")
+	assert.Equal(t, expectedDatabase, setResult.GetDatabase(keyDatabase), "should return a database value from the arguments")
+	assert.Equal(t, setResult, set.GetResults(), "get result function must return parsed data")
+	assert.Len(t, set.GetCollection(), 3, "collection must contain only parameters of the set")
+}
+
+func TestParameterSet_Independent(t *testing.T) {
+	first := NewSet("first")
+	first.AddInt(keyInt, 1, "The int Parameter", false)
+	first.AddBool("FIRST_ONLY", false, "The bool Parameter", false)
+
+	second := NewSet("second")
+	second.AddInt(keyInt, 2, "The int Parameter", false)
+
+	firstResult := first.Parse([]string{"--" + keyInt + "=10"})
+	secondResult := second.Parse([]string{})
+
+	assert.Equal(t, 10, firstResult.GetInt(keyInt), "should return a value from the arguments of the first set")
+	assert.Equal(t, 2, secondResult.GetInt(keyInt), "should return a default value of the second set")
+	assert.Equal(t, expectedInt, result.GetInt(keyInt), "the default set must not be affected")
+	assert.Nil(t, flag.Lookup("FIRST_ONLY"), "flags of the set must not be registered in the command line")
+}
+
+func TestParameterSet_ParseEMissing(t *testing.T) {
+	set := NewSet("test")
+	set.AddString(keyRequiredField, "", "The required Parameter", true)
+	set.AddString(keyNotExist, "", "The required Parameter", true)
+
+	_, err := set.ParseE([]string{})
+
+	var missingErr *MissingParametersError
+	assert.True(t, errors.As(err, &missingErr), "an error should be MissingParametersError")
+	assert.Equal(t, []string{keyNotExist, keyRequiredField}, missingErr.Names, "an error should contain names of missing parameters")
+}
+
+func TestParameterSet_ParseERetry(t *testing.T) {
+	set := NewSet("test")
+	set.AddString(keyNotExist, "", "The required Parameter", true)
+	set.AddInt(keyInt, 50, "The int Parameter", false)
+
+	_, err := set.ParseE([]string{"--" + keyInt + "=10"})
+
+	var missingErr *MissingParametersError
+	assert.True(t, errors.As(err, &missingErr), "an error should be MissingParametersError")
+
+	_ = os.Setenv(keyNotExist, "value")
+	defer os.Unsetenv(keyNotExist)
+
+	setResult, err := set.ParseE([]string{"--" + keyInt + "=10"})
+
+	assert.Nil(t, err, "an error of the retry should be nil")
+	assert.Equal(t, "value", setResult.GetString(keyNotExist), "should return a value provided after the failure")
+	assert.Equal(t, 10, setResult.GetInt(keyInt), "should keep a value of the parsed arguments")
+}
+
+func TestParameterSet_ParseEInvalidFlag(t *testing.T) {
+	set := NewSet("test")
+	set.AddInt(keyInt, 50, "The int Parameter", false)
+
+	_, err := set.ParseE([]string{"--" + keyInt + "=abc"})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, keyInt, invalidErr.Name, "an error should contain name of the parameter")
+	assert.Equal(t, SourceFlag, invalidErr.Source, "an error should contain source of the value")
+	assert.Equal(t, "abc", invalidErr.Raw, "an error should contain raw value")
+}
+
+func TestParameterSet_ParseEEmpty(t *testing.T) {
+	_, err := NewSet("test").ParseE([]string{})
+	assert.Equal(t, ErrNoParameters, err, "an error should be ErrNoParameters")
+}
+
+func TestParameterSet_ParsePanics(t *testing.T) {
+	set := NewSet("test")
+	set.AddString(keyRequiredField, "", "The required Parameter", true)
+
+	assert.Panics(t, func() { set.Parse([]string{}) }, "parse must panic if required parameters weren't provided")
+}
+
+func TestParameterSet_ParseEInvalidEnv(t *testing.T) {
+	const keyPort = "SET_INVALID_PORT"
+
+	_ = os.Setenv(keyPort, "abc")
+	defer os.Unsetenv(keyPort)
+
+	set := NewSet("test")
+	set.AddInt(keyPort, 50, "The int Parameter", false)
+
+	_, err := set.ParseE([]string{})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, keyPort, invalidErr.Name, "an error should contain name of the parameter")
+	assert.Equal(t, SourceEnv, invalidErr.Source, "an error should contain source of the value")
+	assert.Equal(t, "abc", invalidErr.Raw, "an error should contain raw value")
+	assert.True(t, errors.Is(err, flags.ErrParse), "an error should wrap the parse error")
+}
+
+func TestConvertString(t *testing.T) {
+	value, err := convertString(expectInt, intType)
+	assert.Equal(t, expectedInt, value, "should return an int value")
+	assert.Nil(t, err, "an error should be nil")
+
+	_, err = convertString("99999999999999999999", intType)
+	assert.Equal(t, flags.ErrRange, err, "an error should be ErrRange")
+
+	_, err = convertString("0x10", intType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse, a base prefix is accepted only by flags")
+
+	_, err = convertString("99999999999999999999", int64Type)
+	assert.Equal(t, flags.ErrRange, err, "an error should be ErrRange")
+
+	_, err = convertString("-1", uint64Type)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+
+	_, err = convertString("yes", boolType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+
+	value, err = convertString(expectDuration, durationType)
+	assert.Equal(t, expectedDuration, value, "should return a duration value")
+	assert.Nil(t, err, "an error should be nil")
+
+	_, err = convertString("90", durationType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+
+	_, err = convertString("{", databaseType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+}
+
+func TestConvertString_Integers(t *testing.T) {
+	for _, typeValue := range []string{intType, uintType, int64Type, uint64Type} {
+		for str, expected := range map[string]uint64{"010": 10, "08": 8, "42": 42} {
+			value, err := convertString(str, typeValue)
+			assert.Nil(t, err, "an error should be nil")
+			assert.EqualValues(t, expected, value, "should parse %v of %v in the base 10", str, typeValue)
+		}
+	}
+
+	value, err := convertString("010, 08", intSliceType)
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, []int{10, 8}, value, "should parse items in the base 10")
+}
+
+func TestIntegerFlags(t *testing.T) {
+	values := []flag.Value{&flags.IntValue{}, &flags.UintValue{}, &flags.Int64Value{}, &flags.Uint64Value{}}
+	for _, value := range values {
+		assert.Nil(t, value.Set("0x10"), "an error should be nil")
+		assert.EqualValues(t, 16, value.(flag.Getter).Get(), "a flag should accept a base prefix")
+	}
+
+	int64Flag := &flags.Int64Value{}
+	assert.Nil(t, int64Flag.Set("9223372036854775807"), "an error should be nil")
+	assert.Equal(t, int64(math.MaxInt64), int64Flag.Get(), "the int64 flag should accept 64-bit values")
+}
+
+func TestConvertParameterValue_RedactsSecrets(t *testing.T) {
+	param := Parameter{Name: keyDatabase, valueType: databaseType}
+
+	_, err := convertParameterValue(param, "{\"password\":", SourceSecretsManager)
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, redactedValue, invalidErr.Raw, "a raw value of a secret should be redacted")
+}
+
+func TestParameterSet_Slices(t *testing.T) {
+	const (
+		keyHosts   = "SET_HOSTS"
+		keyPorts   = "SET_PORTS"
+		keyWeights = "SET_WEIGHTS"
+	)
+
+	_ = os.Setenv(keyPorts, "80, 443")
+	defer os.Unsetenv(keyPorts)
+
+	set := NewSet("test")
+	set.AddStringSlice(keyHosts, []string{"localhost"}, "The []string Parameter", false)
+	set.AddIntSlice(keyPorts, []int{8080}, "The []int Parameter", false)
+	set.AddFloat64Slice(keyWeights, []float64{1}, "The []float64 Parameter", false)
+
+	setResult, err := set.ParseE([]string{"--" + keyHosts + "=a.example.com,b.example.com", "--" + keyHosts + "=c.example.com"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, []string{"a.example.com", "b.example.com", "c.example.com"}, setResult.GetStringSlice(keyHosts), "should return values of repeated flags")
+	assert.Equal(t, []int{80, 443}, setResult.GetIntSlice(keyPorts), "should return values of a comma-separated env variable")
+	assert.Equal(t, []float64{1}, setResult.GetFloat64Slice(keyWeights), "should return a default value")
+
+	_, err = setResult.GetIntSliceSafe(keyHosts)
+	assert.NotNil(t, err, "an error should not be nil")
+}
+
+func TestConvertString_Slices(t *testing.T) {
+	value, err := convertString("[\"a\",\"b,c\"]", stringSliceType)
+	assert.Equal(t, []string{"a", "b,c"}, value, "should return values of a JSON array")
+	assert.Nil(t, err, "an error should be nil")
+
+	value, err = convertString("1.5,2", float64SliceType)
+	assert.Equal(t, []float64{1.5, 2}, value, "should return values of a comma-separated list")
+	assert.Nil(t, err, "an error should be nil")
+
+	_, err = convertString("1,a", intSliceType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+
+	_, err = convertString("[1,", intSliceType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+}
+
+func TestConvertString_SlicesLikeFlags(t *testing.T) {
+	stringsFlag := &flags.StringSliceValue{}
+	intsFlag := &flags.IntSliceValue{}
+
+	assert.Nil(t, stringsFlag.Set("a, b"), "an error should be nil")
+	assert.Nil(t, intsFlag.Set("16, 2"), "an error should be nil")
+
+	value, err := convertString("a, b", stringSliceType)
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, stringsFlag.Get(), value, "should parse items like the flag")
+	assert.Equal(t, []string{"a", "b"}, value, "should trim items")
+
+	value, err = convertString("16, 2", intSliceType)
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, intsFlag.Get(), value, "should parse items like the flag")
+	assert.Equal(t, []int{16, 2}, value, "should trim items")
+}
+
+func TestParameterSet_StringMap(t *testing.T) {
+	const (
+		keyHeaders = "SET_HEADERS"
+		keyTenants = "SET_TENANTS"
+	)
+
+	_ = os.Setenv(keyTenants, "a=1, b=2")
+	defer os.Unsetenv(keyTenants)
+
+	set := NewSet("test")
+	set.AddStringMap(keyHeaders, map[string]string{"default": "true"}, "The map Parameter", false)
+	set.AddStringMap(keyTenants, nil, "The map Parameter", false)
+
+	setResult, err := set.ParseE([]string{"--" + keyHeaders + "=a=1,b=x=y", "--" + keyHeaders + "=c=3"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, map[string]string{"a": "1", "b": "x=y", "c": "3"}, setResult.GetStringMap(keyHeaders), "should return pairs of repeated flags")
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, setResult.GetStringMap(keyTenants), "should return pairs of an env variable")
+}
+
+func TestConvertString_StringMap(t *testing.T) {
+	value, err := convertString("{\"title\":\"some title\",\"port\":5432}", stringMapType)
+	assert.Equal(t, map[string]string{"title": "some title", "port": "5432"}, value, "should return values of a JSON object")
+	assert.Nil(t, err, "an error should be nil")
+
+	_, err = convertString("a=1,b", stringMapType)
+	assert.Equal(t, flags.ErrParse, err, "an error should be ErrParse")
+}
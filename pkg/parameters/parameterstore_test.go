@@ -0,0 +1,142 @@
+package parameters
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+// newParameterStoreServer starts a server emulating the AWS SSM Parameter Store API
+func newParameterStoreServer(t *testing.T, values map[string]string) (*httptest.Server, *[]string) {
+	targets := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input struct {
+			Name string
+			Path string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+
+		target := r.Header.Get("X-Amz-Target")
+		targets = append(targets, target)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+		switch target {
+		case "AmazonSSM.GetParameter":
+			value, ok := values[input.Name]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"__type": "ParameterNotFound", "message": input.Name})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Parameter": map[string]string{"Name": input.Name, "Value": value, "Type": "String"},
+			})
+		case "AmazonSSM.GetParametersByPath":
+			parameters := make([]map[string]string, 0)
+			for name, value := range values {
+				if len(name) > len(input.Path) && name[:len(input.Path)+1] == input.Path+"/" {
+					parameters = append(parameters, map[string]string{"Name": name, "Value": value, "Type": "String"})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"Parameters": parameters})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	return server, &targets
+}
+
+func newAWSConfig(server *httptest.Server) *aws.Config {
+	return &aws.Config{
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}
+}
+
+func TestParameterSet_ParameterStore(t *testing.T) {
+	server, targets := newParameterStoreServer(t, map[string]string{"PS_HOST": "ps.example.com", "PS_PORT": "5432"})
+	defer server.Close()
+
+	_ = os.Setenv("PS_PORT", "1")
+	defer os.Unsetenv("PS_PORT")
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(server))
+	set.AddString("PS_HOST", "localhost", "A host", false, Options{ParameterStoreEnable: true})
+	set.AddInt("PS_PORT", 0, "A port", false, Options{ParameterStoreEnable: true})
+	set.AddString("PS_USER", "admin", "A user", false, Options{ParameterStoreEnable: true})
+
+	setResult, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "ps.example.com", setResult.GetString("PS_HOST"), "should return a value from Parameter Store")
+	assert.Equal(t, 1, setResult.GetInt("PS_PORT"), "env should be preferred over Parameter Store")
+	assert.Equal(t, "admin", setResult.GetString("PS_USER"), "should return a default value if a parameter not found")
+	assert.Equal(t, []string{"AmazonSSM.GetParameter", "AmazonSSM.GetParameter"}, *targets, "should request each parameter")
+}
+
+func TestParameterSet_ParameterStorePath(t *testing.T) {
+	server, targets := newParameterStoreServer(t, map[string]string{
+		"/app/dev/PS_HOST":  "dev.example.com",
+		"/app/dev/PS_PORT":  "5432",
+		"/app/prod/PS_HOST": "prod.example.com",
+	})
+	defer server.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(server))
+	set.SetParameterStorePath("/app/{stage}/{name}")
+	set.AddString("STAGE", "", "A stage", false)
+	set.AddString("PS_HOST", "localhost", "A host", false, Options{ParameterStoreEnable: true})
+	set.AddInt("PS_PORT", 0, "A port", false, Options{ParameterStoreEnable: true})
+
+	setResult, err := set.ParseE([]string{"--STAGE=DEV"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "dev.example.com", setResult.GetString("PS_HOST"), "should return a value of the stage")
+	assert.Equal(t, 5432, setResult.GetInt("PS_PORT"), "should convert a value to the parameter type")
+	assert.Equal(t, []string{"AmazonSSM.GetParametersByPath"}, *targets, "should load the path by one request")
+}
+
+func TestParameterSet_ParameterStoreErrors(t *testing.T) {
+	server, _ := newParameterStoreServer(t, map[string]string{"PS_PORT": "abc"})
+	defer server.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(server))
+	set.AddInt("PS_PORT", 0, "A port", false, Options{ParameterStoreEnable: true})
+
+	_, err := set.ParseE([]string{})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, SourceParameterStore, invalidErr.Source, "an error should contain a name of the source")
+}
+
+func TestParameterSet_ParameterStoreErrorsRedacted(t *testing.T) {
+	server, _ := newParameterStoreServer(t, map[string]string{"PS_PORT": "secret-value"})
+	defer server.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(server))
+	set.AddInt("PS_PORT", 0, "A port", false, Options{ParameterStoreEnable: true})
+
+	_, err := set.ParseE([]string{})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, redactedValue, invalidErr.Raw, "a raw value of Parameter Store should be redacted")
+	assert.NotContains(t, err.Error(), "secret-value", "an error message shouldn't contain the value")
+}
@@ -0,0 +1,103 @@
+package parameters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Lookup describes a lookup of a parameter value in a source.
+// Name is the exact name consulted in the source, e.g. a flag name, an env variable name or a secret name.
+type Lookup struct {
+	Source string
+	Name   string
+	Found  bool
+}
+
+// Provenance describes an origin of a parameter value.
+// Source and Name describe the lookup which provided the value, Source is SourceDefault if no lookup found the value.
+// Lookups contains all lookups of the parameter in order.
+type Provenance struct {
+	Source  string
+	Name    string
+	Lookups []Lookup
+}
+
+// Source returns a provenance of the value of the parameter. Returns false if the parameter wasn't resolved by Parse.
+// Provenance is kept by the set, not by Results, because Results is a plain map and its copies can't refer to the set.
+func (s *ParameterSet) Source(key string) (Provenance, bool) {
+	provenance, ok := s.provenance[key]
+
+	return provenance, ok
+}
+
+// Provided returns true if a value of the parameter was provided by a source, not by a default value.
+func (s *ParameterSet) Provided(key string) bool {
+	provenance, ok := s.Source(key)
+
+	return ok && provenance.Source != SourceDefault
+}
+
+// Explain returns a diagnostic report which lists every parameter, an origin of its value and lookups that missed.
+// Values aren't included in the report.
+func (s *ParameterSet) Explain() string {
+	keys := make([]string, 0, len(s.result))
+	for key := range s.result {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	report := strings.Builder{}
+
+	for _, key := range keys {
+		provenance, ok := s.Source(key)
+		if !ok {
+			_, _ = fmt.Fprintf(&report, "%v: unknown\n", key)
+			continue
+		}
+
+		if provenance.Source == SourceDefault {
+			_, _ = fmt.Fprintf(&report, "%v: %v\n", key, SourceDefault)
+		} else {
+			_, _ = fmt.Fprintf(&report, "%v: %v %v\n", key, provenance.Source, provenance.Name)
+		}
+
+		for _, lookup := range provenance.Lookups {
+			if !lookup.Found {
+				_, _ = fmt.Fprintf(&report, "  missed: %v %v\n", lookup.Source, lookup.Name)
+			}
+		}
+	}
+
+	return report.String()
+}
+
+// trace records a lookup of the parameter value, lookups after Parse aren't recorded
+func (s *ParameterSet) trace(param Parameter, source string, name string, found bool) {
+	if s.parsed {
+		return
+	}
+
+	if s.lookups == nil {
+		s.lookups = map[string][]Lookup{}
+	}
+
+	s.lookups[param.Name] = append(s.lookups[param.Name], Lookup{Source: source, Name: name, Found: found})
+}
+
+// setProvenance sets a provenance of the parameter value from recorded lookups
+func (s *ParameterSet) setProvenance(param Parameter, found bool) {
+	if s.provenance == nil {
+		s.provenance = map[string]Provenance{}
+	}
+
+	lookups := s.lookups[param.Name]
+	provenance := Provenance{Source: SourceDefault, Lookups: lookups}
+
+	if found && len(lookups) > 0 {
+		provenance.Source = lookups[len(lookups)-1].Source
+		provenance.Name = lookups[len(lookups)-1].Name
+	}
+
+	s.provenance[param.Name] = provenance
+}
@@ -0,0 +1,66 @@
+package parameters
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSet_Source(t *testing.T) {
+	_ = os.Setenv("PROVENANCE_HOST", "env.example.com")
+	defer os.Unsetenv("PROVENANCE_HOST")
+
+	set := NewSet("test")
+	set.SetNaming(Naming{KebabFlags: true})
+	set.SetSources(FlagSource(), EnvSource(), mapSource{"PROVENANCE_USER": "map"})
+	set.AddString("PROVENANCE_HOST", "", "A host", false)
+	set.AddInt("PROVENANCE_PORT", 0, "A port", false)
+	set.AddString("PROVENANCE_USER", "", "A user", false)
+	set.AddString("PROVENANCE_NAME", "default", "A name", false)
+
+	_, err := set.ParseE([]string{"--provenance-port=1"})
+	assert.Nil(t, err, "an error should be nil")
+
+	provenance, ok := set.Source("PROVENANCE_PORT")
+	assert.True(t, ok, "should return a provenance")
+	assert.Equal(t, Provenance{Source: SourceFlag, Name: "provenance-port", Lookups: []Lookup{
+		{Source: SourceFlag, Name: "provenance-port", Found: true},
+	}}, provenance, "should return a provenance of a flag")
+
+	provenance, _ = set.Source("PROVENANCE_HOST")
+	assert.Equal(t, SourceEnv, provenance.Source, "should return a provenance of an env variable")
+	assert.Equal(t, "PROVENANCE_HOST", provenance.Name, "should return a name of an env variable")
+
+	provenance, _ = set.Source("PROVENANCE_USER")
+	assert.Equal(t, "map", provenance.Source, "should return a name of a custom source")
+
+	provenance, _ = set.Source("PROVENANCE_NAME")
+	assert.Equal(t, Provenance{Source: SourceDefault, Lookups: []Lookup{
+		{Source: SourceFlag, Name: "provenance-name"},
+		{Source: SourceEnv, Name: "PROVENANCE_NAME"},
+		{Source: "map", Name: "PROVENANCE_NAME"},
+	}}, provenance, "should return all missed lookups of a default value")
+
+	_, ok = set.Source("UNKNOWN")
+	assert.False(t, ok, "should return false for an unknown key")
+
+	_, ok = NewSet("test").Source("PROVENANCE_HOST")
+	assert.False(t, ok, "should return false for the set which wasn't parsed")
+}
+
+func TestParameterSet_Explain(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("EXPLAIN_HOST", "localhost", "A host", false)
+	set.AddInt("EXPLAIN_PORT", 0, "A port", false)
+
+	_, err := set.ParseE([]string{"--EXPLAIN_PORT=1"})
+	assert.Nil(t, err, "an error should be nil")
+
+	expected := "EXPLAIN_HOST: default\n" +
+		"  missed: flag EXPLAIN_HOST\n" +
+		"  missed: env EXPLAIN_HOST\n" +
+		"EXPLAIN_PORT: flag EXPLAIN_PORT\n"
+
+	assert.Equal(t, expected, set.Explain(), "should list origins and missed lookups of all parameters")
+}
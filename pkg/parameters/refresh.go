@@ -0,0 +1,195 @@
+package parameters
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotParsed is returned if an operation requires parsed parameters.
+var ErrNotParsed = errors.New("parameters haven't been parsed")
+
+// Refresher periodically re-resolves parameters which values were found in the AWS Secrets Manager,
+// so rotated secrets are used without restarting. The results returned by Parse aren't updated,
+// the refresher provides a thread-safe view of the results instead.
+type Refresher struct {
+	set       *ParameterSet
+	interval  time.Duration
+	mu        sync.RWMutex
+	results   Results
+	callbacks []func(name string, oldValue interface{}, newValue interface{})
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewRefresher returns a refresher of the default set. See ParameterSet.NewRefresher for details.
+func NewRefresher(interval time.Duration) *Refresher {
+	return defaultParams.NewRefresher(interval)
+}
+
+// NewRefresher returns a refresher which re-resolves secrets of the set with the specified interval after Start.
+// NewRefresher panics if the set hasn't been parsed or the interval isn't positive.
+func (s *ParameterSet) NewRefresher(interval time.Duration) *Refresher {
+	if !s.parsed {
+		log.Panic(ErrNotParsed)
+	}
+
+	if interval <= 0 {
+		log.Panicf("the interval of the refresher must be positive, got %v", interval)
+	}
+
+	results := make(Results, len(s.result))
+	for key, value := range s.result {
+		results[key] = value
+	}
+
+	return &Refresher{set: s, interval: interval, results: results}
+}
+
+// OnChange registers a callback which is invoked when a value of the parameter is changed by the refresher.
+func (r *Refresher) OnChange(callback func(name string, oldValue interface{}, newValue interface{})) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.callbacks = append(r.callbacks, callback)
+}
+
+// Get returns a current value of the parameter.
+func (r *Refresher) Get(key string) interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.results[key]
+}
+
+// Results returns a copy of current values of all parameters.
+func (r *Refresher) Results() Results {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make(Results, len(r.results))
+	for key, value := range r.results {
+		results[key] = value
+	}
+
+	return results
+}
+
+// Redacted returns a copy of current values of all parameters where values of sensitive parameters are redacted.
+func (r *Refresher) Redacted() Results {
+	return r.set.Redact(r.Results())
+}
+
+// Start starts refreshing of secrets in the background. Errors of refreshing are logged.
+func (r *Refresher) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		return
+	}
+
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go r.run(r.stop, r.done)
+}
+
+// Stop stops refreshing and waits until the current refresh is finished.
+func (r *Refresher) Stop() {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (r *Refresher) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx := context.Background()
+			cancel := func() {}
+			if r.set.timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, r.set.timeout)
+			}
+
+			if err := r.Refresh(ctx); err != nil {
+				log.Errorf("unable to refresh parameters: %v", err)
+			}
+
+			cancel()
+		}
+	}
+}
+
+// Refresh re-resolves secrets once and invokes callbacks of changed parameters.
+// A value is kept if the secret can't be found or its value is invalid, the first error is returned.
+func (r *Refresher) Refresh(ctx context.Context) error {
+	names := make([]string, 0)
+	for name, provenance := range r.set.provenance {
+		if provenance.Source == SourceSecretsManager {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var firstErr error
+
+	for _, name := range names {
+		param := r.set.collection[name]
+		r.set.forgetSecrets(param)
+
+		raw, found, err := r.set.lookupSecret(ctx, param)
+		if err == nil && found {
+			var value interface{}
+			value, err = convertParameterValue(param, raw, SourceSecretsManager)
+			if err == nil {
+				if failures := validateValue(param, value); len(failures) > 0 {
+					err = &ValidationError{Failures: failures}
+				} else {
+					r.update(name, value)
+				}
+			}
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// update sets a new value of the parameter and invokes callbacks if the value is changed
+func (r *Refresher) update(name string, value interface{}) {
+	r.mu.Lock()
+	oldValue := r.results[name]
+	changed := !reflect.DeepEqual(oldValue, value)
+	if changed {
+		r.results[name] = value
+	}
+	callbacks := r.callbacks
+	r.mu.Unlock()
+
+	if changed {
+		for _, callback := range callbacks {
+			callback(name, oldValue, value)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package parameters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefresher_Refresh(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{"REFRESH_PASSWORD": "old", "REFRESH_PORT": "1"}, 0)
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("REFRESH_PASSWORD", "", "A password", true, Options{SecretsManagerEnable: true})
+	set.AddInt("REFRESH_PORT", 0, "A port", false, Options{SecretsManagerEnable: true})
+	set.AddString("REFRESH_USER", "", "A user", false, Options{SecretsManagerEnable: true})
+
+	setResult, err := set.ParseE([]string{"--REFRESH_USER=flag"})
+	assert.Nil(t, err, "an error should be nil")
+
+	refresher := set.NewRefresher(time.Minute)
+
+	changes := make([]string, 0)
+	refresher.OnChange(func(name string, oldValue interface{}, newValue interface{}) {
+		changes = append(changes, name+":"+oldValue.(string)+"->"+newValue.(string))
+	})
+
+	stub.setSecret("REFRESH_PASSWORD", "new")
+	stub.setSecret("REFRESH_PORT", "abc")
+	stub.setSecret("REFRESH_USER", "secret")
+
+	err = refresher.Refresh(context.Background())
+
+	var invalidErr *InvalidValueError
+	assert.ErrorAs(t, err, &invalidErr, "should return an error of the invalid value")
+	assert.Equal(t, []string{"REFRESH_PASSWORD:old->new"}, changes, "should invoke callbacks of changed parameters")
+	assert.Equal(t, "new", refresher.Get("REFRESH_PASSWORD"), "should return a new value")
+	assert.Equal(t, 1, refresher.Get("REFRESH_PORT"), "an invalid value should be ignored")
+	assert.Equal(t, "flag", refresher.Results()["REFRESH_USER"], "a value of a flag shouldn't be refreshed")
+	assert.Equal(t, redactedValue, refresher.Redacted()["REFRESH_PASSWORD"], "a new value of the secret should be redacted")
+	assert.Equal(t, "old", setResult.GetString("REFRESH_PASSWORD"), "results of Parse shouldn't be changed")
+}
+
+func TestRefresher_Start(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{"REFRESH_PASSWORD": "old"}, 0)
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("REFRESH_PASSWORD", "", "A password", true, Options{SecretsManagerEnable: true})
+
+	_, err := set.ParseE([]string{})
+	assert.Nil(t, err, "an error should be nil")
+
+	changed := make(chan interface{}, 1)
+
+	refresher := set.NewRefresher(10 * time.Millisecond)
+	refresher.OnChange(func(name string, oldValue interface{}, newValue interface{}) {
+		changed <- newValue
+	})
+	refresher.Start()
+	defer refresher.Stop()
+
+	stub.setSecret("REFRESH_PASSWORD", "new")
+
+	select {
+	case value := <-changed:
+		assert.Equal(t, "new", value, "should refresh the value in the background")
+	case <-time.After(time.Second):
+		t.Error("the value wasn't refreshed")
+	}
+}
+
+func TestParameterSet_NewRefresherNotParsed(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("REFRESH_PASSWORD", "", "A password", false)
+
+	assert.Panics(t, func() { set.NewRefresher(time.Minute) }, "should panic if the set hasn't been parsed")
+}
+
+func TestParameterSet_NewRefresherInvalidInterval(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("REFRESH_HOST", "localhost", "A host", false)
+
+	_, err := set.ParseE([]string{})
+	assert.Nil(t, err, "an error should be nil")
+
+	assert.Panics(t, func() {
+		set.NewRefresher(0)
+	}, "should panic if the interval isn't positive")
+}
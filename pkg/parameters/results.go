@@ -2,13 +2,33 @@ package parameters
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/barchart/common-go/pkg/configuration/database"
+	"github.com/barchart/common-go/pkg/parameters/flags"
 )
 
 // Results is a structure for parsed parameters.
 type Results map[string]interface{}
 
+// Redact returns a copy of the results where values of sensitive parameters of the set are redacted.
+// Passwords of databases are always redacted. The results can be a copy of the results returned by Parse, e.g. Refresher.Results.
+func (s *ParameterSet) Redact(results Results) Results {
+	redacted := make(Results, len(results))
+
+	for key, value := range results {
+		if param, ok := s.lookupParameter(key); ok && param.IsSensitive() {
+			redacted[key] = redactedValue
+		} else if db, ok := value.(database.Database); ok {
+			redacted[key] = flags.RedactDatabase(db)
+		} else {
+			redacted[key] = value
+		}
+	}
+
+	return redacted
+}
+
 // GetString returns a string value from the results structure by key.
 func (r Results) GetString(key string) string {
 	return r[key].(string)
@@ -49,6 +69,31 @@ func (r Results) GetDatabase(key string) database.Database {
 	return r[key].(database.Database)
 }
 
+// GetDuration returns a time.Duration value from the results structure by key.
+func (r Results) GetDuration(key string) time.Duration {
+	return r[key].(time.Duration)
+}
+
+// GetStringMap returns a map[string]string value from the results structure by key.
+func (r Results) GetStringMap(key string) map[string]string {
+	return r[key].(map[string]string)
+}
+
+// GetStringSlice returns a []string value from the results structure by key.
+func (r Results) GetStringSlice(key string) []string {
+	return r[key].([]string)
+}
+
+// GetIntSlice returns a []int value from the results structure by key.
+func (r Results) GetIntSlice(key string) []int {
+	return r[key].([]int)
+}
+
+// GetFloat64Slice returns a []float64 value from the results structure by key.
+func (r Results) GetFloat64Slice(key string) []float64 {
+	return r[key].([]float64)
+}
+
 // GetStringSafe returns a string value from the results structure by key. Returns an error if the type of value is different than string.
 func (r Results) GetStringSafe(key string) (string, error) {
 	switch r[key].(type) {
@@ -128,3 +173,53 @@ func (r Results) GetDatabaseSafe(key string) (database.Database, error) {
 		return database.Database{}, fmt.Errorf("the %s variable isn't type Database", key)
 	}
 }
+
+// GetDurationSafe returns a time.Duration value from the results structure by key. Returns an error if the type of value is different than time.Duration.
+func (r Results) GetDurationSafe(key string) (time.Duration, error) {
+	switch r[key].(type) {
+	case time.Duration:
+		return r[key].(time.Duration), nil
+	default:
+		return 0, fmt.Errorf("the %s variable isn't type time.Duration", key)
+	}
+}
+
+// GetStringSliceSafe returns a []string value from the results structure by key. Returns an error if the type of value is different than []string.
+func (r Results) GetStringSliceSafe(key string) ([]string, error) {
+	switch r[key].(type) {
+	case []string:
+		return r[key].([]string), nil
+	default:
+		return nil, fmt.Errorf("the %s variable isn't type []string", key)
+	}
+}
+
+// GetIntSliceSafe returns a []int value from the results structure by key. Returns an error if the type of value is different than []int.
+func (r Results) GetIntSliceSafe(key string) ([]int, error) {
+	switch r[key].(type) {
+	case []int:
+		return r[key].([]int), nil
+	default:
+		return nil, fmt.Errorf("the %s variable isn't type []int", key)
+	}
+}
+
+// GetFloat64SliceSafe returns a []float64 value from the results structure by key. Returns an error if the type of value is different than []float64.
+func (r Results) GetFloat64SliceSafe(key string) ([]float64, error) {
+	switch r[key].(type) {
+	case []float64:
+		return r[key].([]float64), nil
+	default:
+		return nil, fmt.Errorf("the %s variable isn't type []float64", key)
+	}
+}
+
+// GetStringMapSafe returns a map[string]string value from the results structure by key. Returns an error if the type of value is different than map[string]string.
+func (r Results) GetStringMapSafe(key string) (map[string]string, error) {
+	switch r[key].(type) {
+	case map[string]string:
+		return r[key].(map[string]string), nil
+	default:
+		return nil, fmt.Errorf("the %s variable isn't type map[string]string", key)
+	}
+}
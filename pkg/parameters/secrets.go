@@ -0,0 +1,241 @@
+package parameters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
+)
+
+// defaultSecretsWorkers is the default number of concurrent requests to the AWS Secrets Manager during Parse
+const defaultSecretsWorkers = 8
+
+// secretValue is a cached result of the request to the AWS Secrets Manager
+type secretValue struct {
+	value string
+	err   error
+}
+
+// secretsEnabled returns true if the parameter is searched in the AWS Secrets Manager
+func (s *ParameterSet) secretsEnabled(param Parameter) bool {
+	return param.Options.SecretsManagerEnable && !s.offline
+}
+
+// splitSecretKey splits the SecretKey option to a secret name and a key of the field.
+// The parameter name is used as the secret name if the option doesn't contain the NAME#key syntax.
+func splitSecretKey(name string, secretKey string) (string, string) {
+	if i := strings.Index(secretKey, "#"); i >= 0 {
+		return secretKey[:i], secretKey[i+1:]
+	}
+
+	return name, secretKey
+}
+
+// secretBases returns names of secrets without a stage: the secret of the SecretKey option or the parameter name and its aliases
+func secretBases(param Parameter) []string {
+	if secret, _ := splitSecretKey(param.Name, param.Options.SecretKey); secret != param.Name {
+		return []string{secret}
+	}
+
+	return param.names()
+}
+
+// secretNames returns names of secrets of the parameter in order of the lookup: NAME_STAGE, then NAME, then names of aliases.
+// The StageSensitive parameter is searched only by names with the stage.
+func (s *ParameterSet) secretNames(param Parameter) []string {
+	bases := secretBases(param)
+	names := make([]string, 0, len(bases)*2)
+
+	for i, base := range bases {
+		if stage, ok := s.result[StageParameter]; ok {
+			names = append(names, s.naming.secretName(fmt.Sprintf("%v_%v", base, strings.ToUpper(stage.(string)))))
+		}
+
+		if !param.Options.StageSensitive {
+			if i == 0 {
+				names = append(names, param.secretName)
+			} else {
+				names = append(names, s.naming.secretName(base))
+			}
+		}
+	}
+
+	return names
+}
+
+// getSecret returns a value of the secret from the cache or the AWS Secrets Manager.
+// Values and missing secrets are cached.
+func (s *ParameterSet) getSecret(ctx context.Context, name string) (string, error) {
+	s.secretsMu.Lock()
+	cached, ok := s.secrets[name]
+	s.secretsMu.Unlock()
+
+	if ok {
+		return cached.value, cached.err
+	}
+
+	sm, err := s.secretsManager()
+	if err != nil {
+		return "", err
+	}
+
+	value, _, err := sm.GetValueWithContext(ctx, name)
+
+	// transient errors, e.g. throttling or a timeout, aren't cached, so the secret is requested again by a retry of Parse
+	if err != nil && !errors.Is(err, secretsmanager.ErrNotFound) {
+		return value, err
+	}
+
+	s.secretsMu.Lock()
+	if s.secrets == nil {
+		s.secrets = map[string]secretValue{}
+	}
+	s.secrets[name] = secretValue{value: value, err: err}
+	s.secretsMu.Unlock()
+
+	return value, err
+}
+
+// forgetSecrets removes cached secrets of the parameter, so they are requested again
+func (s *ParameterSet) forgetSecrets(param Parameter) {
+	s.secretsMu.Lock()
+	defer s.secretsMu.Unlock()
+
+	for _, name := range s.secretNames(param) {
+		delete(s.secrets, name)
+	}
+}
+
+// secretField returns a value of the field of the key/value secret.
+// A value which isn't a string is returned as JSON. Returns false if the secret isn't a JSON object or the field doesn't exist.
+func secretField(secret string, key string) (string, bool) {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(secret), &object); err != nil {
+		return "", false
+	}
+
+	field, ok := object[key]
+	if !ok {
+		return "", false
+	}
+
+	if value, ok := field.(string); ok {
+		return value, true
+	}
+
+	value, _ := json.Marshal(field)
+
+	return string(value), true
+}
+
+// prefetchSecrets requests secrets of parameters concurrently by the bounded pool of workers and caches them.
+// Secrets are requested in two waves: NAME_STAGE of all parameters first, then NAME and names of aliases
+// only of parameters which weren't found by NAME_STAGE, so a secret of the stage doesn't cost a request of NAME.
+// Names are de-duplicated. The AWS SDK doesn't provide a batch API of the AWS Secrets Manager,
+// so each secret is requested separately.
+func (s *ParameterSet) prefetchSecrets(ctx context.Context, params []Parameter) {
+	if len(params) == 0 {
+		return
+	}
+
+	if _, err := s.secretsManager(); err != nil {
+		return
+	}
+
+	_, staged := s.result[StageParameter]
+	requested := map[string]bool{}
+
+	if staged {
+		names := make([]string, 0, len(params))
+		for _, param := range params {
+			names = append(names, s.secretNames(param)[0])
+		}
+
+		s.requestSecrets(ctx, names, requested)
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		paramNames := s.secretNames(param)
+		if staged {
+			if s.isSecretFound(param, paramNames[0]) {
+				continue
+			}
+			paramNames = paramNames[1:]
+		}
+
+		names = append(names, paramNames...)
+	}
+
+	s.requestSecrets(ctx, names, requested)
+}
+
+// isSecretFound returns true if the cached secret exists and contains the key of the parameter
+func (s *ParameterSet) isSecretFound(param Parameter, name string) bool {
+	s.secretsMu.Lock()
+	cached, ok := s.secrets[name]
+	s.secretsMu.Unlock()
+
+	if !ok || cached.err != nil {
+		return false
+	}
+
+	if param.secretKey != "" {
+		_, ok = secretField(cached.value, param.secretKey)
+	}
+
+	return ok
+}
+
+// requestSecrets requests the secrets concurrently by the bounded pool of workers.
+// Names which were requested already are skipped.
+func (s *ParameterSet) requestSecrets(ctx context.Context, names []string, requested map[string]bool) {
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !requested[name] {
+			requested[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	if len(unique) == 0 {
+		return
+	}
+
+	workers := s.secretsWorkers
+	if workers <= 0 {
+		workers = defaultSecretsWorkers
+	}
+
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+
+	jobs := make(chan string)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				_, _ = s.getSecret(ctx, name)
+			}
+		}()
+	}
+
+	for _, name := range unique {
+		jobs <- name
+	}
+
+	close(jobs)
+	wg.Wait()
+}
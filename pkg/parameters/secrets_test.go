@@ -0,0 +1,245 @@
+package parameters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+// secretsManagerStub is a server emulating the AWS Secrets Manager API
+type secretsManagerStub struct {
+	*httptest.Server
+	mu        sync.Mutex
+	secrets   map[string]string
+	requests  map[string]int
+	throttled int
+}
+
+// setSecret sets a value of the secret
+func (stub *secretsManagerStub) setSecret(name string, value string) {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	stub.secrets[name] = value
+}
+
+func newSecretsManagerStub(t testing.TB, secrets map[string]string, latency time.Duration) *secretsManagerStub {
+	stub := &secretsManagerStub{secrets: secrets, requests: map[string]int{}}
+
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input struct {
+			SecretId string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Error(err)
+		}
+
+		stub.mu.Lock()
+		stub.requests[input.SecretId]++
+		throttled := stub.throttled > 0
+		stub.throttled--
+		value, ok := stub.secrets[input.SecretId]
+		stub.mu.Unlock()
+
+		time.Sleep(latency)
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+		if throttled {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"__type": "ThrottlingException", "Message": "Rate exceeded"})
+			return
+		}
+
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"__type": "ResourceNotFoundException", "Message": input.SecretId})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"Name": input.SecretId, "SecretString": value})
+	}))
+
+	return stub
+}
+
+func TestParameterSet_PrefetchSecrets(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{
+		"SECRETS_HOST":     "example.com",
+		"SECRETS_PORT_DEV": "5432",
+		"SECRETS_USER":     "admin",
+	}, 0)
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("STAGE", "dev", "A stage", false)
+	set.AddString("SECRETS_HOST", "", "A host", false, Options{SecretsManagerEnable: true})
+	set.AddInt("SECRETS_PORT", 0, "A port", false, Options{SecretsManagerEnable: true})
+	set.AddString("SECRETS_USER", "", "A user", false, Options{SecretsManagerEnable: true, StageSensitive: true})
+	set.AddString("SECRETS_NAME", "default", "A name", false, Options{SecretsManagerEnable: true})
+	set.AddString("SECRETS_FLAG", "", "A flag", false, Options{SecretsManagerEnable: true})
+
+	setResult, err := set.ParseE([]string{"--SECRETS_FLAG=flag"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "example.com", setResult.GetString("SECRETS_HOST"), "should return a secret without a stage")
+	assert.Equal(t, 5432, setResult.GetInt("SECRETS_PORT"), "should return a secret of the stage")
+	assert.Equal(t, "", setResult.GetString("SECRETS_USER"), "a stage sensitive parameter should be searched only with the stage")
+	assert.Equal(t, "default", setResult.GetString("SECRETS_NAME"), "should return a default value")
+	assert.Equal(t, "flag", setResult.GetString("SECRETS_FLAG"), "a flag should be preferred over secrets")
+
+	assert.Equal(t, map[string]int{
+		"SECRETS_HOST_DEV": 1,
+		"SECRETS_HOST":     1,
+		"SECRETS_PORT_DEV": 1,
+		"SECRETS_USER_DEV": 1,
+		"SECRETS_NAME_DEV": 1,
+		"SECRETS_NAME":     1,
+	}, stub.requests, "each secret should be requested once, NAME shouldn't be requested if NAME_STAGE exists")
+
+	provenance, _ := set.Source("SECRETS_HOST")
+	assert.Equal(t, []Lookup{
+		{Source: SourceFlag, Name: "SECRETS_HOST"},
+		{Source: SourceEnv, Name: "SECRETS_HOST"},
+		{Source: SourceSecretsManager, Name: "SECRETS_HOST_DEV"},
+		{Source: SourceSecretsManager, Name: "SECRETS_HOST", Found: true},
+	}, provenance.Lookups, "lookups of prefetched secrets should be recorded")
+}
+
+func TestParameterSet_SecretKey(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{
+		"database":      `{"host":"example.com","port":5432,"replicas":["a","b"]}`,
+		"SECRETS_TOKEN": `{"value":"token"}`,
+		"SECRETS_PLAIN": "plain",
+		"database_DEV":  `{"host":"dev.example.com"}`,
+	}, 0)
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("SECRETS_HOST", "", "A host", false, Options{SecretsManagerEnable: true, SecretKey: "database#host"})
+	set.AddInt("SECRETS_PORT", 0, "A port", false, Options{SecretsManagerEnable: true, SecretKey: "database#port"})
+	set.AddStringSlice("SECRETS_REPLICAS", nil, "Replicas", false, Options{SecretsManagerEnable: true, SecretKey: "database#replicas"})
+	set.AddString("SECRETS_TOKEN", "", "A token", false, Options{SecretsManagerEnable: true, SecretKey: "value"})
+	set.AddString("SECRETS_PLAIN", "default", "A plain secret", false, Options{SecretsManagerEnable: true, SecretKey: "value"})
+
+	setResult, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "example.com", setResult.GetString("SECRETS_HOST"), "should return a field of the shared secret")
+	assert.Equal(t, 5432, setResult.GetInt("SECRETS_PORT"), "should convert a field to the parameter type")
+	assert.Equal(t, []string{"a", "b"}, setResult.GetStringSlice("SECRETS_REPLICAS"), "should convert a JSON field to the parameter type")
+	assert.Equal(t, "token", setResult.GetString("SECRETS_TOKEN"), "should return a field of the secret of the parameter")
+	assert.Equal(t, "default", setResult.GetString("SECRETS_PLAIN"), "a secret which isn't JSON shouldn't be used")
+	assert.Equal(t, 1, stub.requests["database"], "the shared secret should be requested once")
+
+	provenance, _ := set.Source("SECRETS_HOST")
+	assert.Equal(t, "database#host", provenance.Name, "should return a name of the secret with the key")
+	assert.Equal(t, "database", set.collection["SECRETS_HOST"].SecretName(), "should return a name of the shared secret")
+	assert.Equal(t, "host", set.collection["SECRETS_HOST"].SecretKey(), "should return a key of the field")
+
+	set = NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.AddString("STAGE", "dev", "A stage", false)
+	set.AddString("SECRETS_HOST", "", "A host", false, Options{SecretsManagerEnable: true, SecretKey: "database#host"})
+	set.AddInt("SECRETS_PORT", 0, "A port", false, Options{SecretsManagerEnable: true, SecretKey: "database#port"})
+
+	setResult, err = set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "dev.example.com", setResult.GetString("SECRETS_HOST"), "should return a field of the secret of the stage")
+	assert.Equal(t, 5432, setResult.GetInt("SECRETS_PORT"), "should return a field of the secret without a stage if the field doesn't exist")
+}
+
+func TestParameterSet_SecretsRetry(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{"SECRETS_HOST": "example.com"}, 0)
+	stub.throttled = 2
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.SetSecretsRetryPolicy(secretsmanager.RetryPolicy{MaxRetries: 2, MinDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	set.AddString("SECRETS_HOST", "", "A host", true, Options{SecretsManagerEnable: true})
+
+	setResult, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "example.com", setResult.GetString("SECRETS_HOST"), "should return a secret after retries")
+	assert.Equal(t, 3, stub.requests["SECRETS_HOST"], "throttled requests should be retried")
+}
+
+func TestParameterSet_SecretsRetryParse(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{"SECRETS_HOST": "example.com"}, 0)
+	stub.throttled = 2
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.SetSecretsRetryPolicy(secretsmanager.RetryPolicy{MaxRetries: 0})
+	set.AddString("SECRETS_HOST", "", "A host", true, Options{SecretsManagerEnable: true})
+
+	// the prefetch and the lookup are throttled
+	_, err := set.ParseE([]string{})
+
+	var missingErr *MissingParametersError
+	assert.True(t, errors.As(err, &missingErr), "an error should be MissingParametersError if retries are exhausted")
+
+	setResult, err := set.ParseE([]string{})
+
+	assert.Nil(t, err, "an error of the retry should be nil")
+	assert.Equal(t, "example.com", setResult.GetString("SECRETS_HOST"), "a throttled request should not be cached")
+}
+
+func TestParameterSet_SetLookupTimeout(t *testing.T) {
+	stub := newSecretsManagerStub(t, map[string]string{"SECRETS_HOST": "example.com"}, 500*time.Millisecond)
+	defer stub.Close()
+
+	set := NewSet("test")
+	set.SetAWSConfig(newAWSConfig(stub.Server))
+	set.SetLookupTimeout(50 * time.Millisecond)
+	set.AddString("SECRETS_HOST", "localhost", "A host", false, Options{SecretsManagerEnable: true})
+
+	started := time.Now()
+	_, err := set.ParseE([]string{})
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "an error should be context.DeadlineExceeded")
+	assert.Less(t, int64(time.Since(started)), int64(500*time.Millisecond), "lookups should be canceled by the timeout")
+}
+
+// BenchmarkParameterSet_ParseSecrets compares sequential and concurrent lookups of 20 secrets with 5ms latency.
+func BenchmarkParameterSet_ParseSecrets(b *testing.B) {
+	secrets := map[string]string{}
+	for i := 0; i < 20; i++ {
+		secrets[fmt.Sprintf("BENCHMARK_%v", i)] = "value"
+	}
+
+	stub := newSecretsManagerStub(b, secrets, 5*time.Millisecond)
+	defer stub.Close()
+
+	for _, workers := range []int{1, defaultSecretsWorkers} {
+		b.Run(fmt.Sprintf("workers=%v", workers), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				set := NewSet("benchmark")
+				set.secretsWorkers = workers
+				set.SetAWSConfig(newAWSConfig(stub.Server))
+				for name := range secrets {
+					set.AddString(name, "", "A secret", true, Options{SecretsManagerEnable: true})
+				}
+
+				if _, err := set.ParseE([]string{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package parameters
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSet_Redact(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("SENSITIVE_HOST", "localhost", "A host", false)
+	set.AddString("SENSITIVE_PASSWORD", "secret", "A password", false, Options{Sensitive: true})
+
+	setResult, err := set.ParseE([]string{})
+	assert.Nil(t, err, "an error should be nil")
+
+	assert.Equal(t, "secret", setResult.GetString("SENSITIVE_PASSWORD"), "a value should be available by key")
+	assert.Equal(t, Results{"SENSITIVE_HOST": "localhost", "SENSITIVE_PASSWORD": redactedValue}, set.Redact(setResult), "a sensitive value should be redacted")
+
+	copied := Results{}
+	for key, value := range setResult {
+		copied[key] = value
+	}
+
+	assert.Equal(t, "map[SENSITIVE_HOST:localhost SENSITIVE_PASSWORD:******]", fmt.Sprintf("%v", set.Redact(copied)), "a sensitive value of a copy should be redacted")
+	assert.Equal(t, Results{"KEY": "value"}, set.Redact(Results{"KEY": "value"}), "unknown keys shouldn't be redacted")
+}
+
+func TestParameterSet_RedactDatabase(t *testing.T) {
+	set := NewSet("test")
+	set.AddDatabase("SENSITIVE_DATABASE", expectedDatabase, "A database", false)
+
+	setResult, err := set.ParseE([]string{})
+	assert.Nil(t, err, "an error should be nil")
+
+	redacted := expectedDatabase
+	redacted.Password = redactedValue
+
+	param := set.collection["SENSITIVE_DATABASE"]
+	formatted := "{\"provider\":\"mysql\",\"host\":\"https://example.com\",\"port\":54321,\"database\":\"database\",\"username\":\"user\",\"password\":\"******\"}"
+
+	assert.Equal(t, expectedDatabase, setResult.GetDatabase("SENSITIVE_DATABASE"), "the results should hold a plain value")
+	assert.Equal(t, redacted, set.Redact(setResult)["SENSITIVE_DATABASE"], "a password should be redacted")
+	assert.Equal(t, formatted, param.Format(expectedDatabase), "a password should be redacted by Format")
+	assert.Equal(t, formatted, param.FormatSafe(expectedDatabase), "a password should be redacted by FormatSafe")
+	assert.Equal(t, formatted, set.flags.Lookup("SENSITIVE_DATABASE").Value.String(), "a password of the flag should be redacted")
+}
+
+func TestParameterSet_SensitiveFlag(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("SENSITIVE_PASSWORD", "secret", "A password", false, Options{Sensitive: true})
+	set.AddString("SENSITIVE_TOKEN", "", "A token", false, Options{SecretsManagerEnable: true})
+
+	flg := set.flags.Lookup("SENSITIVE_PASSWORD")
+	assert.Equal(t, redactedValue, flg.DefValue, "a default value should be redacted")
+
+	setResult, err := set.ParseE([]string{"--SENSITIVE_PASSWORD=password", "--SENSITIVE_TOKEN=token"})
+	assert.Nil(t, err, "an error should be nil")
+
+	assert.Equal(t, redactedValue, flg.Value.String(), "a value of the flag should be redacted")
+	assert.Equal(t, "", set.flags.Lookup("SENSITIVE_TOKEN").DefValue, "an empty default value shouldn't be redacted")
+	assert.Equal(t, "password", setResult.GetString("SENSITIVE_PASSWORD"), "should return a value of the flag")
+	assert.Equal(t, "token", setResult.GetString("SENSITIVE_TOKEN"), "a secret should be sensitive by default")
+}
+
+func TestParameterSet_SensitiveErrors(t *testing.T) {
+	set := NewSet("test")
+	set.AddInt("SENSITIVE_PIN", 0, "A pin", false, Options{Sensitive: true})
+
+	_, err := set.ParseE([]string{"--SENSITIVE_PIN=abc"})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, redactedValue, invalidErr.Raw, "a raw value of the flag should be redacted")
+
+	_ = os.Setenv("SENSITIVE_PIN", "abc")
+	defer os.Unsetenv("SENSITIVE_PIN")
+
+	set = NewSet("test")
+	set.AddInt("SENSITIVE_PIN", 0, "A pin", false, Options{Sensitive: true})
+
+	_, err = set.ParseE([]string{})
+
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, redactedValue, invalidErr.Raw, "a raw value of the env variable should be redacted")
+	assert.NotContains(t, err.Error(), "abc", "an error shouldn't contain the value")
+}
+
+func TestParameter_IsSensitive(t *testing.T) {
+	set := NewSet("test")
+	set.AddString("SENSITIVE_HOST", "", "A host", false)
+	set.AddString("SENSITIVE_PASSWORD", "", "A password", false, Options{SecretsManagerEnable: true})
+	set.AddString("SENSITIVE_DB_HOST", "", "A host of database", false, Options{SecretsManagerEnable: true, NotSensitive: true})
+	set.AddString("SENSITIVE_TOKEN", "", "A token", false, Options{Sensitive: true, NotSensitive: true})
+
+	assert.False(t, set.collection["SENSITIVE_HOST"].IsSensitive(), "a parameter shouldn't be sensitive by default")
+	assert.True(t, set.collection["SENSITIVE_PASSWORD"].IsSensitive(), "a secret should be sensitive by default")
+	assert.False(t, set.collection["SENSITIVE_DB_HOST"].IsSensitive(), "a secret with the NotSensitive option shouldn't be sensitive")
+	assert.True(t, set.collection["SENSITIVE_TOKEN"].IsSensitive(), "the Sensitive option should take precedence")
+}
@@ -0,0 +1,595 @@
+package parameters
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/barchart/common-go/pkg/configuration/aws/parameterstore"
+	"github.com/barchart/common-go/pkg/configuration/aws/secretsmanager"
+	"github.com/barchart/common-go/pkg/configuration/database"
+	"github.com/barchart/common-go/pkg/parameters/flags"
+)
+
+// ParameterSet is a set of defined parameters. Each set has its own flag set, collection and results,
+// so several independent sets can be defined in one binary.
+type ParameterSet struct {
+	name           string
+	flags          *flags.FlagSet
+	collection     map[string]Parameter
+	result         Results
+	parsed         bool
+	sm             *secretsmanager.SecretsManager
+	smError        error
+	bindings       []binding
+	naming         Naming
+	configFile     string
+	fileValues     map[string]string
+	dotenvFiles    []string
+	dotenv         map[string]dotenvValue
+	sources        []Source
+	ps             *parameterstore.ParameterStore
+	psError        error
+	psPath         string
+	psCache        map[string]map[string]string
+	awsConfigs     []*aws.Config
+	lookups        map[string][]Lookup
+	provenance     map[string]Provenance
+	secrets        map[string]secretValue
+	secretsMu      sync.Mutex
+	secretsWorkers int
+	secretsRetry   *secretsmanager.RetryPolicy
+	timeout        time.Duration
+	offline        bool
+	setFlags       map[string]bool
+	awsMu          sync.Mutex
+	constraints    []Constraint
+	stageFlags     []string
+	flagsParsed    bool
+}
+
+// NewSet returns a new, empty parameter set with the specified name.
+func NewSet(name string) *ParameterSet {
+	return newSet(name, flags.NewFlagSet(name, flag.ContinueOnError))
+}
+
+func newSet(name string, fs *flags.FlagSet) *ParameterSet {
+	return &ParameterSet{
+		name:       name,
+		flags:      fs,
+		collection: map[string]Parameter{},
+		result:     map[string]interface{}{},
+	}
+}
+
+// SetNaming sets a naming strategy of flags, environment variables and secrets.
+// It must be called before parameters are added to the set.
+func (s *ParameterSet) SetNaming(naming Naming) {
+	s.naming = naming
+}
+
+// SetConfigFile sets a path of the JSON or YAML config file with values of parameters.
+// The path can be overridden by the CONFIG flag or env variable.
+func (s *ParameterSet) SetConfigFile(path string) {
+	s.configFile = path
+}
+
+// SetSources sets the ordered list of sources of parameter values, e.g. to prefer secrets over environment variables:
+//
+//	set.SetSources(parameters.FlagSource(), parameters.SecretsManagerSource(), parameters.EnvSource())
+//
+// The default order is FlagSource, EnvSource, FileSource, SecretsManagerSource, ParameterStoreSource.
+// A default value is used if the value wasn't found in all sources.
+func (s *ParameterSet) SetSources(sources ...Source) {
+	s.sources = sources
+}
+
+// SetStageFlags sets stages of flags of stage sensitive parameters, e.g. "DEV", "PROD".
+// Parse defines a flag with a suffix of each stage for each stage sensitive parameter, e.g. --NAME_PROD,
+// and the flag of the value of the STAGE parameter takes precedence over the flag without the suffix.
+func (s *ParameterSet) SetStageFlags(stages ...string) {
+	s.stageFlags = stages
+}
+
+// SetParameterStorePath sets a template of names of parameters in AWS SSM Parameter Store, e.g. /app/{stage}/{name}.
+// The {name} placeholder is replaced by the parameter name and the {stage} placeholder is replaced by a lower case value
+// of the STAGE parameter. If the template is a hierarchical path ending with /{name}, all parameters under the path
+// are loaded by one GetParametersByPath request. By default, the parameter name is used.
+func (s *ParameterSet) SetParameterStorePath(template string) {
+	s.psPath = template
+}
+
+// SetAWSConfig sets additional configs of AWS clients, e.g. to set an endpoint or credentials.
+func (s *ParameterSet) SetAWSConfig(configs ...*aws.Config) {
+	s.awsConfigs = configs
+}
+
+// SetLookupTimeout sets a limit of the total time spent on lookups of values during Parse, e.g. requests to the AWS Secrets Manager.
+// ParseE returns an error which wraps context.DeadlineExceeded if the limit is exceeded. By default, lookups aren't limited.
+func (s *ParameterSet) SetLookupTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// SetOffline enables the offline mode. AWS Secrets Manager and AWS SSM Parameter Store are skipped in the offline mode.
+// The offline mode can be enabled by the OFFLINE flag or env variable too.
+func (s *ParameterSet) SetOffline(offline bool) {
+	s.offline = offline
+}
+
+// SetSecretsRetryPolicy sets a policy of retries of throttled requests and internal service errors of the AWS Secrets Manager.
+// By default, secretsmanager.DefaultRetryPolicy is used.
+func (s *ParameterSet) SetSecretsRetryPolicy(policy secretsmanager.RetryPolicy) {
+	s.secretsRetry = &policy
+}
+
+// SetDotEnvFiles sets paths of dotenv files which are loaded by Parse, e.g. ".env", ".env.{stage}".
+// A later file overrides values of previous files, real environment variables override values of all files.
+// Files with the {stage} placeholder are loaded after the STAGE parameter is resolved, the placeholder is replaced
+// by a lower case value of the STAGE parameter. Missing files are skipped.
+func (s *ParameterSet) SetDotEnvFiles(files ...string) {
+	s.dotenvFiles = files
+}
+
+// LookupDotEnv returns a value of the environment variable loaded from a dotenv file and a path of the file.
+func (s *ParameterSet) LookupDotEnv(name string) (value string, file string, ok bool) {
+	v, ok := s.dotenv[name]
+
+	return v.value, v.file, ok
+}
+
+// define adds a Parameter to the collection and returns it
+func (s *ParameterSet) define(name string, value interface{}, usage string, required bool, options []Options, valueType string) Parameter {
+	opts := parseOptions(options)
+	secret, key := splitSecretKey(name, opts.SecretKey)
+
+	param := Parameter{
+		Name:         name,
+		DefaultValue: value,
+		Usage:        usage,
+		Required:     required,
+		Options:      opts,
+		valueType:    valueType,
+		flagName:     s.naming.flagName(name),
+		envName:      s.naming.envName(name),
+		secretName:   s.naming.secretName(secret),
+		secretKey:    key,
+		set:          s,
+	}
+
+	checkStageDefaults(&param)
+	checkRules(param)
+
+	s.collection[name] = param
+
+	return param
+}
+
+// Name returns the name of the parameter set.
+func (s *ParameterSet) Name() string {
+	return s.name
+}
+
+// Add is alias for AddString
+func (s *ParameterSet) Add(name string, value string, usage string, required bool, options ...Options) {
+	s.AddString(name, value, usage, required, options...)
+}
+
+// AddBool defines a bool Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddBool(name string, value bool, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, boolType)
+
+	s.flags.Bool(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddCustom defines a Parameter of the custom type with specified name, default value, and usage string.
+// The type must be registered by RegisterType.
+func (s *ParameterSet) AddCustom(name string, value interface{}, usage string, required bool, typ string, options ...Options) {
+	custom, ok := getCustomType(typ)
+	if !ok {
+		log.Panicf("the %v type isn't registered", typ)
+	}
+
+	param := s.define(name, value, usage, required, options, typ)
+
+	s.flags.Custom(param.flagName, value, usage, custom.parse, custom.format)
+	s.setupFlag(param)
+}
+
+// AddDatabase defines a database Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddDatabase(name string, value database.Database, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, databaseType)
+
+	s.flags.Database(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddDuration defines a time.Duration Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddDuration(name string, value time.Duration, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, durationType)
+
+	s.flags.Duration(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddFloat64 defines a float64 Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddFloat64(name string, value float64, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, float64Type)
+
+	s.flags.Float64(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddFloat64Slice defines a []float64 Parameter with specified name, default value, and usage string.
+// The value is provided by repeated flags, a comma-separated environment variable or a JSON array secret.
+func (s *ParameterSet) AddFloat64Slice(name string, value []float64, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, float64SliceType)
+
+	s.flags.Float64Slice(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddInt defines a int Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddInt(name string, value int, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, intType)
+
+	s.flags.Int(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddInt64 defines a int64 Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddInt64(name string, value int64, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, int64Type)
+
+	s.flags.Int64(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddIntSlice defines a []int Parameter with specified name, default value, and usage string.
+// The value is provided by repeated flags, a comma-separated environment variable or a JSON array secret.
+func (s *ParameterSet) AddIntSlice(name string, value []int, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, intSliceType)
+
+	s.flags.IntSlice(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddString defines a string Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddString(name string, value string, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, stringType)
+
+	s.flags.String(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddStringMap defines a map[string]string Parameter with specified name, default value, and usage string.
+// The value is provided by comma-separated key=value pairs of flags and environment variables or a key/value secret.
+func (s *ParameterSet) AddStringMap(name string, value map[string]string, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, stringMapType)
+
+	s.flags.StringMap(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddStringSlice defines a []string Parameter with specified name, default value, and usage string.
+// The value is provided by repeated flags, a comma-separated environment variable or a JSON array secret.
+func (s *ParameterSet) AddStringSlice(name string, value []string, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, stringSliceType)
+
+	s.flags.StringSlice(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddUint defines a uint Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddUint(name string, value uint, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, uintType)
+
+	s.flags.Uint(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// AddUint64 defines a uint64 Parameter with specified name, default value, and usage string.
+func (s *ParameterSet) AddUint64(name string, value uint64, usage string, required bool, options ...Options) {
+	param := s.define(name, value, usage, required, options, uint64Type)
+
+	s.flags.Uint64(param.flagName, value, usage)
+	s.setupFlag(param)
+}
+
+// Parse parses the argument list, which should not include the command name,
+// and returns map of values of all parameters defined in the set. Parse panics if ParseE returns an error.
+func (s *ParameterSet) Parse(args []string) Results {
+	result, err := s.ParseE(args)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return result
+}
+
+// ParseE parses the argument list, which should not include the command name,
+// and returns map of values of all parameters defined in the set.
+// Returns *MissingParametersError if required parameters weren't provided,
+// *InvalidValueError if a value can't be converted to the parameter type
+// *ValidationError if values don't pass validation rules of parameters
+// and *ConstraintError if values don't satisfy constraints of the set.
+// A failed ParseE can be called again, e.g. after a missing variable is set, the arguments are parsed only once.
+func (s *ParameterSet) ParseE(args []string) (Results, error) {
+	if s.parsed {
+		return s.result, nil
+	}
+
+	if len(s.collection) == 0 {
+		return nil, ErrNoParameters
+	}
+
+	// flags are parsed once, values are resolved again if the previous call failed
+	if !s.flagsParsed {
+		if err := s.parseFlags(args); err != nil {
+			return nil, err
+		}
+	}
+
+	s.dotenv = nil
+	s.lookups = nil
+
+	if err := s.loadDotEnvFiles(false); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadConfigFile(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadOffline(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(s.collection))
+	isStage := false
+
+	for key := range s.collection {
+		if key != StageParameter {
+			keys = append(keys, key)
+		} else {
+			isStage = true
+		}
+	}
+
+	sort.Strings(keys)
+
+	if isStage {
+		keys = append([]string{"STAGE"}, keys...)
+	}
+
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	resolutions := make(map[string]resolution, len(keys))
+	pending := make([]Parameter, 0)
+
+	for _, key := range keys {
+		param := s.collection[key]
+
+		// secret names and dotenv files depend on the STAGE parameter, so it's resolved without waiting for prefetched secrets
+		res, err := s.resolve(ctx, param, 0, key != StageParameter)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.pending >= 0 {
+			pending = append(pending, param)
+		}
+
+		resolutions[key] = res
+
+		if key == StageParameter {
+			s.storeValue(param, res)
+
+			if err := s.loadDotEnvFiles(true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.prefetchSecrets(ctx, pending)
+
+	missing := make([]string, 0, 1)
+	failures := make([]ValidationFailure, 0)
+
+	for _, key := range keys {
+		param := s.collection[key]
+		res := resolutions[key]
+
+		if res.pending >= 0 {
+			var err error
+			res, err = s.resolve(ctx, param, res.pending, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if res.found {
+			failures = append(failures, validateValue(param, res.value)...)
+
+			if param.Options.Deprecated != "" {
+				s.warnDeprecated(param)
+			}
+		} else if param.Required {
+			missing = append(missing, param.Name)
+		} else {
+			failures = append(failures, validateValue(param, s.defaultValue(param))...)
+		}
+
+		s.storeValue(param, res)
+		s.setProvenance(param, res.found)
+	}
+
+	if len(missing) > 0 {
+		return nil, &MissingParametersError{Names: missing}
+	}
+
+	if len(failures) > 0 {
+		return nil, &ValidationError{Failures: failures}
+	}
+
+	if unsatisfied := s.checkConstraints(s.result); len(unsatisfied) > 0 {
+		return nil, &ConstraintError{Constraints: unsatisfied}
+	}
+
+	s.parsed = true
+	s.fillBindings()
+
+	return s.result, nil
+}
+
+// parseFlags defines flags of the package and parses the argument list
+func (s *ParameterSet) parseFlags(args []string) error {
+	if s.flags.Parsed() {
+		return ErrFlagsParsed
+	}
+
+	if s.remoteEnabled() && s.flags.Lookup(AwsRegionSecrets) == nil {
+		s.flags.String(AwsRegionSecrets, defaultAWSRegion, "The AWS Secrets Manager region")
+	}
+
+	if s.flags.Lookup(ConfigParameter) == nil {
+		s.flags.String(ConfigParameter, "", "The JSON or YAML config file with values of parameters")
+	}
+
+	if s.flags.Lookup(OfflineParameter) == nil {
+		s.flags.Bool(OfflineParameter, false, "Skip AWS Secrets Manager and AWS SSM Parameter Store")
+	}
+
+	s.defineStageFlags()
+
+	if err := s.flags.Parse(args); err != nil {
+		var valueErr *flags.ValueError
+		if errors.As(err, &valueErr) {
+			invalidErr := &InvalidValueError{Name: s.nameByFlag(valueErr.Name), Source: SourceFlag, Raw: valueErr.Value, Err: valueErr.Err}
+			if param, ok := s.collection[invalidErr.Name]; ok && param.IsSensitive() {
+				invalidErr.Raw = redactedValue
+			}
+			return invalidErr
+		}
+
+		return err
+	}
+
+	s.visitFlags()
+	s.flagsParsed = true
+
+	return nil
+}
+
+// resolution is a result of the resolution of the parameter value
+type resolution struct {
+	value interface{}
+	found bool
+	// pending is an index of the AWS Secrets Manager source if the resolution waits for prefetched secrets, otherwise -1
+	pending int
+}
+
+// resolve returns a value of the parameter from the first source which has it, starting from the source with the start index.
+// If deferSecrets is true, the resolution stops at the AWS Secrets Manager source, so secrets of all parameters can be prefetched concurrently.
+func (s *ParameterSet) resolve(ctx context.Context, param Parameter, start int, deferSecrets bool) (resolution, error) {
+	sources := s.sources
+	if sources == nil {
+		sources = defaultSources
+	}
+
+	for i := start; i < len(sources); i++ {
+		source := sources[i]
+
+		if _, ok := source.(secretsManagerSource); ok && deferSecrets && s.secretsEnabled(param) {
+			return resolution{pending: i}, nil
+		}
+
+		var value interface{}
+		var found bool
+		var err error
+
+		if r, ok := source.(resolver); ok {
+			value, _, found, err = r.resolve(ctx, param)
+		} else {
+			value, _, found, err = resolveRaw(ctx, source, param, sourceName(source))
+			if err == nil {
+				s.trace(param, sourceName(source), param.Name, found)
+			}
+		}
+
+		if err != nil || found {
+			return resolution{value: value, found: found, pending: -1}, err
+		}
+	}
+
+	return resolution{pending: -1}, nil
+}
+
+// storeValue stores the resolved value or the default value of the parameter in the results
+func (s *ParameterSet) storeValue(param Parameter, res resolution) {
+	if res.found {
+		s.result[param.Name] = res.value
+	} else if !param.Required {
+		s.result[param.Name] = s.defaultValue(param)
+	}
+}
+
+// lookupParameter returns a parameter by name, the set can be nil
+func (s *ParameterSet) lookupParameter(name string) (Parameter, bool) {
+	if s == nil {
+		return Parameter{}, false
+	}
+
+	param, ok := s.collection[name]
+
+	return param, ok
+}
+
+// nameByFlag returns a parameter name by the flag name
+func (s *ParameterSet) nameByFlag(flagName string) string {
+	for _, param := range s.collection {
+		if param.flagName == flagName {
+			return param.Name
+		}
+
+		for _, alias := range param.Options.Aliases {
+			if s.naming.flagName(alias) == flagName {
+				return param.Name
+			}
+		}
+
+		for _, stage := range s.stageFlags {
+			if param.Options.StageSensitive && s.naming.flagName(fmt.Sprintf("%v_%v", param.Name, strings.ToUpper(stage))) == flagName {
+				return param.Name
+			}
+		}
+	}
+
+	return flagName
+}
+
+// GetResults returns a result that already has been parsed.
+func (s *ParameterSet) GetResults() Results {
+	return s.result
+}
+
+// Parsed Returns true if the parse function was used.
+func (s *ParameterSet) Parsed() bool {
+	return s.parsed
+}
+
+// GetCollection returns a collection of parameters.
+func (s *ParameterSet) GetCollection() map[string]Parameter {
+	return s.collection
+}
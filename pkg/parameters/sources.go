@@ -0,0 +1,191 @@
+package parameters
+
+import (
+	"context"
+	"fmt"
+)
+
+// Source is a source of parameter values. Lookup returns a raw value of the parameter and true if the value was found.
+// The raw value is converted to the parameter type the same way as values of environment variables.
+// If a source implements fmt.Stringer, the string is used as a name of the source in errors.
+type Source interface {
+	Lookup(ctx context.Context, param Parameter) (raw string, found bool, err error)
+}
+
+// resolver is implemented by built-in sources which provide converted values
+type resolver interface {
+	resolve(ctx context.Context, param Parameter) (value interface{}, source string, found bool, err error)
+}
+
+// defaultSources is the default order of sources
+var defaultSources = []Source{FlagSource(), EnvSource(), FileSource(), SecretsManagerSource(), ParameterStoreSource()}
+
+// FlagSource returns a source of values of command-line flags.
+func FlagSource() Source {
+	return flagSource{}
+}
+
+// EnvSource returns a source of values of environment variables and variables loaded from dotenv files.
+func EnvSource() Source {
+	return envSource{}
+}
+
+// FileSource returns a source of values of the config file.
+func FileSource() Source {
+	return fileSource{}
+}
+
+// SecretsManagerSource returns a source of values of the AWS Secrets Manager.
+// Only parameters with the SecretsManagerEnable option are searched.
+func SecretsManagerSource() Source {
+	return secretsManagerSource{}
+}
+
+// ParameterStoreSource returns a source of values of AWS SSM Parameter Store.
+// Only parameters with the ParameterStoreEnable option are searched.
+func ParameterStoreSource() Source {
+	return parameterStoreSource{}
+}
+
+type flagSource struct{}
+
+func (flagSource) Lookup(_ context.Context, param Parameter) (string, bool, error) {
+	if flg, ok := param.set.stageFlag(param); ok {
+		if _, isSet := getValueFromFlag(flg, param.valueType); isSet {
+			return flagValue(flg).String(), true, nil
+		}
+	}
+
+	flg := param.set.flags.Lookup(param.flagName)
+	_, isSet := getValueFromFlag(flg, param.valueType)
+
+	return flagValue(flg).String(), isSet, nil
+}
+
+func (flagSource) resolve(_ context.Context, param Parameter) (interface{}, string, bool, error) {
+	if flg, ok := param.set.stageFlag(param); ok {
+		value, isSet := getValueFromFlag(flg, param.valueType)
+		param.set.trace(param, SourceFlag, flg.Name, isSet)
+		if isSet {
+			return value, SourceFlag, true, nil
+		}
+	}
+
+	value, isSet := getValueFromFlag(param.set.flags.Lookup(param.flagName), param.valueType)
+	name := param.flagName
+	if isSet {
+		name = param.set.usedFlagName(param)
+	}
+	param.set.trace(param, SourceFlag, name, isSet)
+
+	return value, SourceFlag, isSet, nil
+}
+
+func (flagSource) String() string {
+	return SourceFlag
+}
+
+type envSource struct{}
+
+func (envSource) Lookup(_ context.Context, param Parameter) (string, bool, error) {
+	raw, _, _, found := param.set.lookupParamEnv(param)
+
+	return raw, found, nil
+}
+
+func (envSource) resolve(_ context.Context, param Parameter) (interface{}, string, bool, error) {
+	raw, source, _, found := param.set.lookupParamEnv(param)
+	if !found {
+		return nil, source, false, nil
+	}
+
+	value, err := convertParameterValue(param, raw, source)
+
+	return value, source, err == nil, err
+}
+
+func (envSource) String() string {
+	return SourceEnv
+}
+
+type fileSource struct{}
+
+func (fileSource) Lookup(_ context.Context, param Parameter) (string, bool, error) {
+	if param.set.fileValues == nil {
+		return "", false, nil
+	}
+
+	for i, name := range param.names() {
+		raw, found := param.set.fileValues[name]
+		param.set.trace(param, SourceFile, name, found)
+		if found {
+			if i > 0 {
+				param.set.warnAlias(param, SourceFile, name)
+			}
+			return raw, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func (f fileSource) resolve(ctx context.Context, param Parameter) (interface{}, string, bool, error) {
+	return resolveRaw(ctx, f, param, SourceFile)
+}
+
+func (fileSource) String() string {
+	return SourceFile
+}
+
+type secretsManagerSource struct{}
+
+func (secretsManagerSource) Lookup(ctx context.Context, param Parameter) (string, bool, error) {
+	return param.set.lookupSecret(ctx, param)
+}
+
+func (sm secretsManagerSource) resolve(ctx context.Context, param Parameter) (interface{}, string, bool, error) {
+	return resolveRaw(ctx, sm, param, SourceSecretsManager)
+}
+
+func (secretsManagerSource) String() string {
+	return SourceSecretsManager
+}
+
+type parameterStoreSource struct{}
+
+func (parameterStoreSource) Lookup(ctx context.Context, param Parameter) (string, bool, error) {
+	return param.set.lookupParameterStore(ctx, param)
+}
+
+func (ps parameterStoreSource) resolve(ctx context.Context, param Parameter) (interface{}, string, bool, error) {
+	return resolveRaw(ctx, ps, param, SourceParameterStore)
+}
+
+func (parameterStoreSource) String() string {
+	return SourceParameterStore
+}
+
+// resolveRaw looks up a raw value in the source and converts it to the parameter type
+func resolveRaw(ctx context.Context, source Source, param Parameter, name string) (interface{}, string, bool, error) {
+	raw, found, err := source.Lookup(ctx, param)
+	if err != nil {
+		return nil, name, false, fmt.Errorf("unable to lookup the %v parameter in the %v source: %w", param.Name, name, err)
+	}
+
+	if !found {
+		return nil, name, false, nil
+	}
+
+	value, err := convertParameterValue(param, raw, name)
+
+	return value, name, err == nil, err
+}
+
+// sourceName returns a name of the source
+func sourceName(source Source) string {
+	if stringer, ok := source.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return SourceCustom
+}
@@ -0,0 +1,69 @@
+package parameters
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapSource map[string]string
+
+func (m mapSource) Lookup(_ context.Context, param Parameter) (string, bool, error) {
+	raw, found := m[param.Name]
+	return raw, found, nil
+}
+
+func (m mapSource) String() string {
+	return "map"
+}
+
+type failingSource struct{}
+
+func (failingSource) Lookup(context.Context, Parameter) (string, bool, error) {
+	return "", false, errors.New("unavailable")
+}
+
+func TestParameterSet_SetSources(t *testing.T) {
+	_ = os.Setenv("SOURCES_HOST", "env.example.com")
+	_ = os.Setenv("SOURCES_PORT", "1")
+	defer os.Unsetenv("SOURCES_HOST")
+	defer os.Unsetenv("SOURCES_PORT")
+
+	set := NewSet("test")
+	set.SetSources(EnvSource(), mapSource{"SOURCES_HOST": "map.example.com", "SOURCES_TIMEOUT": "5"}, FlagSource())
+	set.AddString("SOURCES_HOST", "", "A host", false)
+	set.AddInt("SOURCES_PORT", 0, "A port", false)
+	set.AddInt("SOURCES_TIMEOUT", 0, "A timeout", false)
+	set.AddString("SOURCES_NAME", "default", "A name", false)
+
+	setResult, err := set.ParseE([]string{"--SOURCES_PORT=2", "--SOURCES_NAME=flag"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "env.example.com", setResult.GetString("SOURCES_HOST"), "env should be preferred over a custom source")
+	assert.Equal(t, 1, setResult.GetInt("SOURCES_PORT"), "env should be preferred over flags")
+	assert.Equal(t, 5, setResult.GetInt("SOURCES_TIMEOUT"), "should return a value from a custom source")
+	assert.Equal(t, "flag", setResult.GetString("SOURCES_NAME"), "should return a value from a flag")
+}
+
+func TestParameterSet_SetSourcesErrors(t *testing.T) {
+	set := NewSet("test")
+	set.SetSources(mapSource{"SOURCES_PORT": "abc"})
+	set.AddInt("SOURCES_PORT", 0, "A port", false)
+
+	_, err := set.ParseE([]string{})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, "map", invalidErr.Source, "an error should contain a name of the custom source")
+
+	set = NewSet("test")
+	set.SetSources(failingSource{})
+	set.AddInt("SOURCES_PORT", 0, "A port", false)
+
+	_, err = set.ParseE([]string{})
+	assert.NotNil(t, err, "should return an error of the source")
+	assert.Contains(t, err.Error(), SourceCustom, "an error should contain a name of the source")
+}
@@ -0,0 +1,129 @@
+package parameters
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/barchart/common-go/pkg/configuration/database"
+)
+
+// checkStageDefaults panics if a stage default value of the parameter has a type other than the type of the default value.
+// Stages are converted to upper case.
+func checkStageDefaults(param *Parameter) {
+	if len(param.Options.StageDefaults) == 0 {
+		return
+	}
+
+	defaults := make(map[string]interface{}, len(param.Options.StageDefaults))
+
+	for stage, value := range param.Options.StageDefaults {
+		if reflect.TypeOf(value) != reflect.TypeOf(param.DefaultValue) {
+			log.Panicf("the %v default value of the %v parameter must be %T, got %T", stage, param.Name, param.DefaultValue, value)
+		}
+
+		defaults[strings.ToUpper(stage)] = value
+	}
+
+	param.Options.StageDefaults = defaults
+}
+
+// defaultValue returns a default value of the parameter for the value of the STAGE parameter
+// or the default value of the parameter if there is no default value for the stage
+func (s *ParameterSet) defaultValue(param Parameter) interface{} {
+	if stage, ok := s.result[StageParameter].(string); ok && param.Name != StageParameter {
+		if value, ok := param.Options.StageDefaults[strings.ToUpper(stage)]; ok {
+			return value
+		}
+	}
+
+	return param.DefaultValue
+}
+
+// stageName returns the name with a suffix of the upper case value of the STAGE parameter, e.g. NAME_PROD.
+// Returns false if the parameter isn't stage sensitive or the STAGE parameter doesn't have a value.
+func (s *ParameterSet) stageName(param Parameter, name string) (string, bool) {
+	if !param.Options.StageSensitive || param.Name == StageParameter {
+		return "", false
+	}
+
+	stage, ok := s.result[StageParameter].(string)
+	if !ok || stage == "" {
+		return "", false
+	}
+
+	return fmt.Sprintf("%v_%v", name, strings.ToUpper(stage)), true
+}
+
+// stageFlag returns the flag of the parameter for the value of the STAGE parameter if the flag is defined by SetStageFlags
+func (s *ParameterSet) stageFlag(param Parameter) (*flag.Flag, bool) {
+	name, ok := s.stageName(param, param.Name)
+	if !ok {
+		return nil, false
+	}
+
+	flg := s.flags.Lookup(s.naming.flagName(name))
+
+	return flg, flg != nil
+}
+
+// defineStageFlags defines flags of stage sensitive parameters for stages of SetStageFlags, e.g. --NAME_PROD
+func (s *ParameterSet) defineStageFlags() {
+	for _, param := range s.collection {
+		if !param.Options.StageSensitive || param.Name == StageParameter {
+			continue
+		}
+
+		for _, stage := range s.stageFlags {
+			stage = strings.ToUpper(stage)
+			name := s.naming.flagName(fmt.Sprintf("%v_%v", param.Name, stage))
+
+			if s.flags.Lookup(name) != nil {
+				continue
+			}
+
+			s.defineFlag(param, name, fmt.Sprintf("%v (the %v stage)", param.Usage, stage))
+
+			if param.IsSensitive() {
+				s.flags.Sensitive(name)
+			}
+		}
+	}
+}
+
+// defineFlag defines a flag of the parameter type with the name
+func (s *ParameterSet) defineFlag(param Parameter, name string, usage string) {
+	switch param.valueType {
+	case boolType:
+		s.flags.Bool(name, param.DefaultValue.(bool), usage)
+	case databaseType:
+		s.flags.Database(name, param.DefaultValue.(database.Database), usage)
+	case durationType:
+		s.flags.Duration(name, param.DefaultValue.(time.Duration), usage)
+	case float64Type:
+		s.flags.Float64(name, param.DefaultValue.(float64), usage)
+	case float64SliceType:
+		s.flags.Float64Slice(name, param.DefaultValue.([]float64), usage)
+	case intType:
+		s.flags.Int(name, param.DefaultValue.(int), usage)
+	case int64Type:
+		s.flags.Int64(name, param.DefaultValue.(int64), usage)
+	case intSliceType:
+		s.flags.IntSlice(name, param.DefaultValue.([]int), usage)
+	case stringType:
+		s.flags.String(name, param.DefaultValue.(string), usage)
+	case stringMapType:
+		s.flags.StringMap(name, param.DefaultValue.(map[string]string), usage)
+	case stringSliceType:
+		s.flags.StringSlice(name, param.DefaultValue.([]string), usage)
+	case uintType:
+		s.flags.Uint(name, param.DefaultValue.(uint), usage)
+	case uint64Type:
+		s.flags.Uint64(name, param.DefaultValue.(uint64), usage)
+	default:
+		custom, _ := getCustomType(param.valueType)
+		s.flags.Custom(name, param.DefaultValue, usage, custom.parse, custom.format)
+	}
+}
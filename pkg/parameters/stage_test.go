@@ -0,0 +1,114 @@
+package parameters
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStageDefaultsSet() *ParameterSet {
+	set := NewSet("test")
+	set.AddString(StageParameter, "dev", "A stage", false)
+	set.AddString("DB_HOST", "localhost", "A host of database", false, Options{StageDefaults: map[string]interface{}{"stage": "db.stage", "PROD": "db.prod"}})
+	set.AddInt("DB_PORT", 5432, "A port of database", false, Options{StageDefaults: map[string]interface{}{"PROD": 0}, Validate: "min=1"})
+
+	return set
+}
+
+func TestParameterSet_StageDefaults(t *testing.T) {
+	set := newStageDefaultsSet()
+	setResult, err := set.ParseE([]string{"--STAGE=stage"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "db.stage", setResult.GetString("DB_HOST"), "should return a default value of the stage")
+	assert.Equal(t, 5432, setResult.GetInt("DB_PORT"), "should return a default value if there is no default value of the stage")
+
+	provenance, _ := set.Source("DB_HOST")
+	assert.Equal(t, SourceDefault, provenance.Source, "should record the default source")
+
+	setResult, err = newStageDefaultsSet().ParseE([]string{})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "localhost", setResult.GetString("DB_HOST"), "should return a default value if there is no default value of the stage")
+
+	setResult, err = newStageDefaultsSet().ParseE([]string{"--STAGE=prod", "--DB_HOST=db", "--DB_PORT=5433"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "db", setResult.GetString("DB_HOST"), "should return a value of the flag")
+	assert.Equal(t, 5433, setResult.GetInt("DB_PORT"), "should return a value of the flag")
+}
+
+func TestParameterSet_StageDefaultsValidation(t *testing.T) {
+	_, err := newStageDefaultsSet().ParseE([]string{"--STAGE=PROD"})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr), "should validate a default value of the stage")
+	assert.Equal(t, "DB_PORT", validationErr.Failures[0].Name, "should return the parameter name")
+}
+
+func TestParameterSet_StageDefaultsInvalidType(t *testing.T) {
+	set := NewSet("test")
+
+	assert.Panics(t, func() {
+		set.AddInt("DB_PORT", 5432, "A port of database", false, Options{StageDefaults: map[string]interface{}{"PROD": "5433"}})
+	}, "should panic if a type of the default value of the stage is invalid")
+}
+
+func TestParameterSet_StageSensitiveEnv(t *testing.T) {
+	_ = os.Setenv("STAGE_DB_HOST_PROD", "db.prod")
+	_ = os.Setenv("STAGE_DB_HOST", "db")
+	_ = os.Setenv("STAGE_DB_PORT", "5433")
+	_ = os.Setenv("STAGE_DB_PORT_DEV", "5434")
+	defer os.Unsetenv("STAGE_DB_HOST_PROD")
+	defer os.Unsetenv("STAGE_DB_HOST")
+	defer os.Unsetenv("STAGE_DB_PORT")
+	defer os.Unsetenv("STAGE_DB_PORT_DEV")
+
+	set := NewSet("test")
+	set.AddString(StageParameter, "DEV", "A stage", false)
+	set.AddString("STAGE_DB_HOST", "localhost", "A host of database", false, Options{StageSensitive: true})
+	set.AddInt("STAGE_DB_PORT", 5432, "A port of database", false, Options{StageSensitive: true})
+
+	setResult, err := set.ParseE([]string{"--STAGE=prod"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "db.prod", setResult.GetString("STAGE_DB_HOST"), "should return a value of the env variable of the stage")
+	assert.Equal(t, 5433, setResult.GetInt("STAGE_DB_PORT"), "should return a value of the env variable without the stage")
+
+	provenance, _ := set.Source("STAGE_DB_PORT")
+	assert.Equal(t, []Lookup{
+		{Source: SourceFlag, Name: "STAGE_DB_PORT", Found: false},
+		{Source: SourceEnv, Name: "STAGE_DB_PORT_PROD", Found: false},
+		{Source: SourceEnv, Name: "STAGE_DB_PORT", Found: true},
+	}, provenance.Lookups, "should search the env variable of the stage first")
+}
+
+func TestParameterSet_StageFlags(t *testing.T) {
+	set := NewSet("test")
+	set.SetStageFlags("dev", "prod")
+	set.AddString(StageParameter, "DEV", "A stage", false)
+	set.AddString("DB_HOST", "localhost", "A host of database", false, Options{StageSensitive: true})
+	set.AddString("DB_NAME", "app", "A name of database", false)
+
+	setResult, err := set.ParseE([]string{"--STAGE=prod", "--DB_HOST=db", "--DB_HOST_PROD=db.prod", "--DB_HOST_DEV=db.dev"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, "db.prod", setResult.GetString("DB_HOST"), "should return a value of the flag of the stage")
+	assert.Nil(t, set.flags.Lookup("DB_NAME_PROD"), "shouldn't define flags of stages for the parameter which isn't stage sensitive")
+
+	provenance, _ := set.Source("DB_HOST")
+	assert.Equal(t, "DB_HOST_PROD", provenance.Name, "should record the name of the flag of the stage")
+
+	set = NewSet("test")
+	set.SetStageFlags("PROD")
+	set.AddString(StageParameter, "DEV", "A stage", false)
+	set.AddInt("DB_PORT", 5432, "A port of database", false, Options{StageSensitive: true})
+
+	_, err = set.ParseE([]string{"--DB_PORT_PROD=port"})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "should return an InvalidValueError")
+	assert.Equal(t, "DB_PORT", invalidErr.Name, "should return the parameter name of the flag of the stage")
+}
@@ -0,0 +1,73 @@
+package parameters
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ParseFunc converts a raw value from a flag, an environment variable or a secret to a value of the custom type.
+type ParseFunc func(raw string) (interface{}, error)
+
+// FormatFunc converts a value of the custom type to a string.
+type FormatFunc func(value interface{}) string
+
+// customType is a registered custom type of parameters
+type customType struct {
+	parse  ParseFunc
+	format FormatFunc
+}
+
+var (
+	customTypes   = map[string]customType{}
+	customTypesMu sync.RWMutex
+)
+
+// builtinTypes are names of types which can't be registered as custom types
+var builtinTypes = map[string]bool{
+	boolType:         true,
+	databaseType:     true,
+	durationType:     true,
+	float64Type:      true,
+	float64SliceType: true,
+	int64Type:        true,
+	intType:          true,
+	intSliceType:     true,
+	stringType:       true,
+	stringSliceType:  true,
+	stringMapType:    true,
+	uintType:         true,
+	uint64Type:       true,
+}
+
+// RegisterType registers a custom type of parameters with specified name, parse and format functions.
+// Parameters of the custom type are defined by AddCustom. If the format function is nil, fmt.Sprint is used.
+func RegisterType(name string, parse ParseFunc, format FormatFunc) error {
+	if parse == nil {
+		return fmt.Errorf("the parse function of the %v type is required", name)
+	}
+
+	if format == nil {
+		format = func(value interface{}) string { return fmt.Sprint(value) }
+	}
+
+	customTypesMu.Lock()
+	defer customTypesMu.Unlock()
+
+	if _, ok := customTypes[name]; ok || builtinTypes[name] {
+		return fmt.Errorf("the %v type is already registered", name)
+	}
+
+	customTypes[name] = customType{parse: parse, format: format}
+
+	return nil
+}
+
+// getCustomType returns a registered custom type by name
+func getCustomType(name string) (customType, bool) {
+	customTypesMu.RLock()
+	defer customTypesMu.RUnlock()
+
+	typ, ok := customTypes[name]
+
+	return typ, ok
+}
@@ -0,0 +1,82 @@
+package parameters
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const urlType = "url"
+
+func parseURL(raw string) (interface{}, error) {
+	value, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return *value, nil
+}
+
+func formatURL(value interface{}) string {
+	u := value.(url.URL)
+	return u.String()
+}
+
+func init() {
+	_ = RegisterType(urlType, parseURL, formatURL)
+}
+
+func TestRegisterType(t *testing.T) {
+	assert.NotNil(t, RegisterType(urlType, parseURL, formatURL), "should return an error for a registered type")
+	assert.NotNil(t, RegisterType(intType, parseURL, formatURL), "should return an error for a built-in type")
+	assert.NotNil(t, RegisterType("nil", nil, nil), "should return an error without a parse function")
+}
+
+func TestParameterSet_AddCustom(t *testing.T) {
+	const (
+		keyFlagURL    = "CUSTOM_FLAG_URL"
+		keyEnvURL     = "CUSTOM_ENV_URL"
+		keyDefaultURL = "CUSTOM_DEFAULT_URL"
+	)
+
+	_ = os.Setenv(keyEnvURL, "https://env.example.com")
+	defer os.Unsetenv(keyEnvURL)
+
+	defaultURL := url.URL{Scheme: "https", Host: "default.example.com"}
+
+	set := NewSet("test")
+	set.AddCustom(keyFlagURL, url.URL{}, "The url Parameter", false, urlType)
+	set.AddCustom(keyEnvURL, url.URL{}, "The url Parameter", false, urlType)
+	set.AddCustom(keyDefaultURL, defaultURL, "The url Parameter", false, urlType)
+
+	setResult, err := set.ParseE([]string{"--" + keyFlagURL + "=https://flag.example.com/path"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, url.URL{Scheme: "https", Host: "flag.example.com", Path: "/path"}, setResult[keyFlagURL], "should return a value from a flag")
+	assert.Equal(t, url.URL{Scheme: "https", Host: "env.example.com"}, setResult[keyEnvURL], "should return a value from an env variable")
+	assert.Equal(t, defaultURL, setResult[keyDefaultURL], "should return a default value")
+	assert.Equal(t, "https://default.example.com", set.GetCollection()[keyDefaultURL].Format(defaultURL), "should format a value by the format function")
+}
+
+func TestParameterSet_AddCustomInvalid(t *testing.T) {
+	const keyURL = "CUSTOM_INVALID_URL"
+
+	_ = os.Setenv(keyURL, "://")
+	defer os.Unsetenv(keyURL)
+
+	set := NewSet("test")
+	set.AddCustom(keyURL, url.URL{}, "The url Parameter", false, urlType)
+
+	_, err := set.ParseE([]string{})
+
+	var invalidErr *InvalidValueError
+	assert.True(t, errors.As(err, &invalidErr), "an error should be InvalidValueError")
+	assert.Equal(t, SourceEnv, invalidErr.Source, "an error should contain source of the value")
+}
+
+func TestParameterSet_AddCustomNotRegistered(t *testing.T) {
+	assert.Panics(t, func() { NewSet("test").AddCustom("CUSTOM", nil, "", false, "not registered") }, "should panic for a not registered type")
+}
@@ -0,0 +1,81 @@
+package parameters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/barchart/common-go/pkg/validation"
+	"github.com/go-playground/validator"
+)
+
+// ValidationFailure describes a value of the parameter which doesn't pass a validation rule.
+type ValidationFailure struct {
+	Name    string
+	Rule    string
+	Message string
+}
+
+// ValidationError is returned by ParseE if values of parameters don't pass their validation rules.
+type ValidationError struct {
+	Failures []ValidationFailure
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, failure.Message)
+	}
+
+	return fmt.Sprintf("invalid parameters: [ %v ]", strings.Join(messages, " "))
+}
+
+// checkRules panics if validation rules of the parameter are invalid
+func checkRules(param Parameter) {
+	if param.Options.Validate == "" {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Panicf("invalid validation rules of the %v parameter: %v", param.Name, r)
+		}
+	}()
+
+	_ = validation.GetValidator().Var(param.DefaultValue, param.Options.Validate)
+}
+
+// validateValue validates the value by validation rules of the parameter.
+// Errors are translated by validation.Translator, values aren't included into messages.
+func validateValue(param Parameter, value interface{}) []ValidationFailure {
+	if param.Options.Validate == "" {
+		return nil
+	}
+
+	err := validation.GetValidator().Var(value, param.Options.Validate)
+	if err == nil {
+		return nil
+	}
+
+	fieldErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []ValidationFailure{{Name: param.Name, Message: fmt.Sprintf("%v is invalid: %v", param.Name, err)}}
+	}
+
+	failures := make([]ValidationFailure, 0, len(fieldErrors))
+
+	for _, fe := range fieldErrors {
+		// the field name is empty, because a value is validated without a struct
+		message := fe.Translate(validation.Translator)
+		if e, ok := fe.(error); ok && message == e.Error() {
+			message = fmt.Sprintf("failed on the %v rule.", fe.Tag())
+		}
+
+		failures = append(failures, ValidationFailure{
+			Name:    param.Name,
+			Rule:    fe.Tag(),
+			Message: param.Name + " " + strings.TrimSpace(message),
+		})
+	}
+
+	return failures
+}
@@ -0,0 +1,47 @@
+package parameters
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterSet_Validate(t *testing.T) {
+	set := NewSet("test")
+	set.AddInt("VALIDATE_PORT", 8080, "A port", false, Options{Validate: "min=1,max=65535"})
+	set.AddString("VALIDATE_STAGE", "dev", "A stage", false, Options{Validate: "oneof=dev stage prod"})
+	set.AddString("VALIDATE_URL", "", "A URL", false, Options{Validate: "omitempty,url"})
+
+	setResult, err := set.ParseE([]string{"--VALIDATE_PORT=443", "--VALIDATE_STAGE=prod"})
+
+	assert.Nil(t, err, "an error should be nil")
+	assert.Equal(t, 443, setResult.GetInt("VALIDATE_PORT"), "should return a valid value")
+	assert.Equal(t, "prod", setResult.GetString("VALIDATE_STAGE"), "should return a valid value")
+}
+
+func TestParameterSet_ValidateErrors(t *testing.T) {
+	set := NewSet("test")
+	set.AddInt("VALIDATE_PORT", 8080, "A port", false, Options{Validate: "min=1,max=65535"})
+	set.AddString("VALIDATE_STAGE", "dev", "A stage", false, Options{Validate: "oneof=dev stage prod"})
+	set.AddString("VALIDATE_URL", "", "A URL", false, Options{Validate: "url"})
+
+	_, err := set.ParseE([]string{"--VALIDATE_PORT=70000", "--VALIDATE_STAGE=test"})
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr), "an error should be ValidationError")
+	assert.Equal(t, []ValidationFailure{
+		{Name: "VALIDATE_PORT", Rule: "max", Message: "VALIDATE_PORT must be at most 65535."},
+		{Name: "VALIDATE_STAGE", Rule: "oneof", Message: "VALIDATE_STAGE must be one of [dev stage prod]."},
+		{Name: "VALIDATE_URL", Rule: "url", Message: "VALIDATE_URL must be a valid URL."},
+	}, validationErr.Failures, "should aggregate all failures, a default value should be validated too")
+	assert.Equal(t, "invalid parameters: [ VALIDATE_PORT must be at most 65535. VALIDATE_STAGE must be one of [dev stage prod]. VALIDATE_URL must be a valid URL. ]", err.Error())
+}
+
+func TestParameterSet_ValidateInvalidRules(t *testing.T) {
+	set := NewSet("test")
+
+	assert.Panics(t, func() {
+		set.AddInt("VALIDATE_PORT", 8080, "A port", false, Options{Validate: "unknown=1"})
+	}, "should panic if validation rules are invalid")
+}
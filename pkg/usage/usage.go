@@ -3,6 +3,8 @@ package usage
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/barchart/common-go/pkg/parameters"
 )
@@ -23,6 +25,7 @@ type usage struct {
 	appDescription string
 	commands       []command
 	parameters     map[string]parameters.Parameter
+	constraints    []parameters.Constraint
 	arguments      []arguments
 	examples       []string
 }
@@ -53,11 +56,14 @@ func AddCommand(name, description string, args ...string) {
 }
 
 // AddParameters adds parameters description from parameters collection (flags, env, AWS Secrets Manager)
+// and constraints between parameters
 func AddParameters() {
 	collection := parameters.GetCollection()
 	if collection != nil {
 		usg.parameters = collection
 	}
+
+	usg.constraints = parameters.GetConstraints()
 }
 
 // AddArgument adds argument to run without commands
@@ -75,7 +81,7 @@ func AddExample(example string) {
 
 // GetUsage returns a usage string
 func GetUsage() string {
-	return fmt.Sprintf("Usage: \n%v%v%v%v%v%v", getName(), getDescription(), getParameters(), getCommands(), getArguments(), getExamples())
+	return fmt.Sprintf("Usage: \n%v%v%v%v%v%v%v", getName(), getDescription(), getParameters(), getConstraints(), getCommands(), getArguments(), getExamples())
 }
 
 func getName() string {
@@ -125,10 +131,28 @@ func getParameters() string {
 				name = name + "*"
 			}
 
+			if param.IsList() {
+				name = name + " <value>[,<value>...]"
+			} else if param.IsMap() {
+				name = name + " <key>=<value>[,<key>=<value>...]"
+			}
+
 			buf.WriteString(fmt.Sprintf("\t%v", name))
 
+			if names := getNames(param); names != "" {
+				buf.WriteString(fmt.Sprintf("\n\t  %v", names))
+			}
+
+			if param.Options.Deprecated != "" {
+				buf.WriteString(fmt.Sprintf("\n\t  deprecated: %v", param.Options.Deprecated))
+			}
+
+			if defaults := getStageDefaults(param); defaults != "" {
+				buf.WriteString(fmt.Sprintf("\n\t  %v", defaults))
+			}
+
 			if index == len(usg.parameters)-1 {
-				buf.WriteString(fmt.Sprintf("\n\t  %v (default %v)\n", param.Usage, param.DefaultValue))
+				buf.WriteString(fmt.Sprintf("\n\t  %v (default %v)\n", param.Usage, param.FormatSafe(param.DefaultValue)))
 			} else {
 				buf.WriteString(fmt.Sprintf("\n\t  %v\n\n", param.Usage))
 			}
@@ -142,6 +166,68 @@ func getParameters() string {
 	return str
 }
 
+// getStageDefaults returns default values of the parameter by stages sorted by stages
+func getStageDefaults(param parameters.Parameter) string {
+	defaults := make([]string, 0, len(param.Options.StageDefaults))
+
+	for stage, value := range param.Options.StageDefaults {
+		defaults = append(defaults, fmt.Sprintf("%v=%v", stage, param.FormatSafe(value)))
+	}
+
+	if len(defaults) == 0 {
+		return ""
+	}
+
+	sort.Strings(defaults)
+
+	return fmt.Sprintf("stage defaults: %v", strings.Join(defaults, ", "))
+}
+
+func getConstraints() string {
+	str := ""
+
+	if len(usg.constraints) != 0 {
+		buf := bytes.NewBufferString("  Constraints:\n")
+		for _, constraint := range usg.constraints {
+			buf.WriteString(fmt.Sprintf("\t%v\n", constraint))
+		}
+
+		str = buf.String()
+	}
+
+	return str
+}
+
+// getNames returns aliases of the parameter, names of the flag, the environment variable and the secret
+// if they differ from the parameter name and the dotenv file which defines the environment variable
+func getNames(param parameters.Parameter) string {
+	names := make([]string, 0, 4)
+
+	if len(param.Options.Aliases) > 0 {
+		names = append(names, fmt.Sprintf("aliases: %v", strings.Join(param.Options.Aliases, ", ")))
+	}
+
+	if param.FlagName() != param.Name {
+		names = append(names, fmt.Sprintf("flag: --%v", param.FlagName()))
+	}
+
+	if param.EnvName() != param.Name {
+		names = append(names, fmt.Sprintf("env: %v", param.EnvName()))
+	}
+
+	if param.Options.SecretsManagerEnable && param.SecretKey() != "" {
+		names = append(names, fmt.Sprintf("secret: %v#%v", param.SecretName(), param.SecretKey()))
+	} else if param.Options.SecretsManagerEnable && param.SecretName() != param.Name {
+		names = append(names, fmt.Sprintf("secret: %v", param.SecretName()))
+	}
+
+	if _, file, ok := parameters.LookupDotEnv(param.EnvName()); ok {
+		names = append(names, fmt.Sprintf("env file: %v", file))
+	}
+
+	return strings.Join(names, ", ")
+}
+
 func getArguments() string {
 	str := ""
 
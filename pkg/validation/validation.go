@@ -22,6 +22,36 @@ func init() {
 
 		return t
 	})
+
+	for tag, text := range translations {
+		registerTranslation(tag, text)
+	}
+}
+
+// translations are English translations of validation tags, {0} is a field name and {1} is a parameter of the tag
+var translations = map[string]string{
+	"email":    "{0} must be a valid email address.",
+	"gt":       "{0} must be greater than {1}.",
+	"gte":      "{0} must be {1} or greater.",
+	"hostname": "{0} must be a valid hostname.",
+	"len":      "{0} must have a length of {1}.",
+	"lt":       "{0} must be less than {1}.",
+	"lte":      "{0} must be {1} or less.",
+	"max":      "{0} must be at most {1}.",
+	"min":      "{0} must be at least {1}.",
+	"oneof":    "{0} must be one of [{1}].",
+	"url":      "{0} must be a valid URL.",
+}
+
+// registerTranslation registers the translation of the tag for Translator
+func registerTranslation(tag string, text string) {
+	_ = validate.RegisterTranslation(tag, Translator, func(ut ut.Translator) error {
+		return ut.Add(tag, text, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T(tag, fe.Field(), fe.Param())
+
+		return t
+	})
 }
 
 func New() *validator.Validate {